@@ -0,0 +1,62 @@
+package kameleoon
+
+import (
+	"context"
+
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// strictBooleanContextKey is the context.Context key marking a boolean evaluation which requires a bool variable.
+type strictBooleanContextKey struct{}
+
+// WithStrictBoolean makes boolean evaluations require a bool variable in the variation, failing with
+// FLAG_NOT_FOUND otherwise, instead of returning whether the feature flag is active for the visitor.
+func WithStrictBoolean() Option {
+	return func(o *providerOptions) {
+		o.strictBoolean = true
+	}
+}
+
+// withStrictBoolean returns a copy of ctx whose evaluation requires a bool variable.
+func withStrictBoolean(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, strictBooleanContextKey{}, true)
+}
+
+// isStrictBoolean reports whether the evaluation requires a bool variable.
+func isStrictBoolean(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	strict, _ := ctx.Value(strictBooleanContextKey{}).(bool)
+	return strict
+}
+
+// resolveActivation resolves a boolean evaluation by the activation of the feature flag, i.e. whether the variation
+// isn't "off", if the variation has no bool variable and no variable key is requested. It reports false otherwise.
+func resolveActivation(
+	ctx context.Context, evalContext openfeature.FlattenedContext, variables map[string]interface{}, variant string,
+	defaultValue interface{},
+) (resolution, bool) {
+	if _, ok := defaultValue.(bool); !ok {
+		return resolution{}, false
+	}
+	if isStrictBoolean(ctx) {
+		return resolution{}, false
+	}
+	if variableKey, ok := evalContext["variableKey"].(string); ok && variableKey != "" {
+		return resolution{}, false
+	}
+	for _, value := range variables {
+		if _, ok := value.(bool); ok {
+			return resolution{}, false
+		}
+	}
+	if variant == string(types.VariationOff) {
+		return resolution{value: false, variant: variant, reason: openfeature.DefaultReason}, true
+	}
+	return resolution{value: true, variant: variant, reason: openfeature.TargetingMatchReason}, true
+}
@@ -0,0 +1,95 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveVariable_BooleanWithoutBoolVariable_ReturnsActivation(t *testing.T) {
+	tests := []struct {
+		name        string
+		evalContext openfeature.FlattenedContext
+		variables   map[string]interface{}
+		variant     string
+		value       bool
+		reason      openfeature.Reason
+		errorCode   openfeature.ErrorCode
+	}{
+		{"active without variables", openfeature.FlattenedContext{}, nil, "on", true,
+			openfeature.TargetingMatchReason, ""},
+		{"off without variables", openfeature.FlattenedContext{}, nil, "off", false, openfeature.DefaultReason, ""},
+		{"active without bool variable", openfeature.FlattenedContext{}, map[string]interface{}{"title": "Sale"},
+			"on", true, openfeature.TargetingMatchReason, ""},
+		{"bool variable", openfeature.FlattenedContext{}, map[string]interface{}{"enabled": false}, "on", false,
+			"", ""},
+		{"requested variable", openfeature.FlattenedContext{"variableKey": "enabled"}, nil, "on", false, "",
+			openfeature.FlagNotFoundCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			res := resolveVariable(context.Background(), tt.evalContext, tt.variables, tt.variant, false)
+
+			// Assert
+			assert.Equal(t, tt.value, res.value)
+			assert.Equal(t, tt.variant, res.variant)
+			assert.Equal(t, tt.reason, res.reason)
+			if tt.errorCode == "" {
+				assert.Nil(t, res.err)
+			} else {
+				assert.Equal(t, tt.errorCode, openfeature.ProviderResolutionDetail{
+					ResolutionError: *res.err,
+				}.ResolutionDetail().ErrorCode)
+			}
+		})
+	}
+}
+
+func TestResolveVariable_StrictBoolean_RequiresBoolVariable(t *testing.T) {
+	// Act
+	res := resolveVariable(withStrictBoolean(context.Background()), openfeature.FlattenedContext{}, nil, "on", false)
+
+	// Assert
+	assert.Equal(t, false, res.value)
+	assert.Equal(t, openfeature.FlagNotFoundCode, openfeature.ProviderResolutionDetail{
+		ResolutionError: *res.err,
+	}.ResolutionDetail().ErrorCode)
+}
+
+func TestKameleoonProvider_BooleanEvaluation_Toggle(t *testing.T) {
+	// Arrange
+	client := kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "toggle",
+		DefaultVariation: "on",
+		Variations:       []kameleoontest.Variation{{Key: "on"}},
+		Visitors:         map[string]string{"excluded": kameleoontest.VariationOff},
+	})
+	provider := NewKameleoonProviderWithClient("siteCode", client)
+	defer provider.Shutdown()
+	strict := NewKameleoonProviderWithClient("siteCode", client, WithStrictBoolean())
+	defer strict.Shutdown()
+
+	// Act
+	active := provider.BooleanEvaluation(context.Background(), "toggle", false,
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+	excluded := provider.BooleanEvaluation(context.Background(), "toggle", true,
+		openfeature.FlattenedContext{"targetingKey": "excluded"})
+	strictResult := strict.BooleanEvaluation(context.Background(), "toggle", false,
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+	// The strict mode is carried by ctx, the evaluation context of the caller can't enable it.
+	attributeResult := provider.BooleanEvaluation(context.Background(), "toggle", false,
+		openfeature.FlattenedContext{"targetingKey": "visitor", "kameleoonStrictBoolean": true})
+
+	// Assert
+	assert.NoError(t, active.Error())
+	assert.True(t, active.Value)
+	assert.NoError(t, excluded.Error())
+	assert.False(t, excluded.Value)
+	assert.Equal(t, openfeature.FlagNotFoundCode, strictResult.ResolutionDetail().ErrorCode)
+	assert.NoError(t, attributeResult.Error())
+	assert.True(t, attributeResult.Value)
+}
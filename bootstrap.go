@@ -0,0 +1,379 @@
+package kameleoon
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/logging"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// liveRetryInterval is the first delay between checks of a live client whose initialization failed.
+	liveRetryInterval = time.Second
+	// liveMaxRetryInterval is the longest delay between checks of a live client whose initialization failed.
+	liveMaxRetryInterval = time.Minute
+	// maxReplayedCalls is the maximum number of data calls recorded before the switch to the live client.
+	maxReplayedCalls = 10000
+)
+
+// ErrLiveClientNotReady is returned by SwitchToLive if the live client hasn't loaded a configuration yet.
+// Evaluations made before are failed with PROVIDER_NOT_READY.
+var ErrLiveClientNotReady = errors.New("the Kameleoon client hasn't loaded a configuration yet")
+
+// WithBootstrapConfiguration makes the provider evaluate flags from a local configuration file until the
+// remote configuration is loaded by the Kameleoon client.
+//
+// The file has the JSON format of the configuration served by Kameleoon. It can be exported from Kameleoon
+// or written by hand. The provider is ready as soon as the file is loaded and switches to live data,
+// emitting PROVIDER_CONFIGURATION_CHANGED, once the Kameleoon client loads a configuration. If the first load
+// fails, the client keeps polling Kameleoon and the switch happens after its first successful poll.
+//
+// Visitor data and conversions added before the switch are replayed to the Kameleoon client on switch, up to
+// 10000 calls. Exposures aren't replayed since the variations were calculated from the local configuration.
+func WithBootstrapConfiguration(path string) Option {
+	return func(o *providerOptions) {
+		o.bootstrapPath = path
+	}
+}
+
+// WithManualLiveSwitch disables the automatic switch from the bootstrap or stored configuration to live data.
+// The provider keeps serving the local configuration until SwitchToLive is called, e.g. at the end of a
+// deployment, so the variations don't change while it's in progress.
+func WithManualLiveSwitch() Option {
+	return func(o *providerOptions) {
+		o.manualLiveSwitch = true
+	}
+}
+
+// NewOfflineKameleoonProvider creates a new instance of kameleoonProvider which evaluates flags from the local
+// configuration file without any network access. The file has the JSON format of the configuration served by
+// Kameleoon. Visitor data is kept in memory and never sent.
+func NewOfflineKameleoonProvider(siteCode string, configPath string, opts ...Option) (*kameleoonProvider, error) {
+	config, err := loadLocalConfiguration(configPath)
+	if err != nil {
+		return nil, openfeature.NewProviderNotReadyResolutionError(err.Error())
+	}
+	return newProvider(siteCode, newLocalClient(config, ""), newProviderOptions(opts)), nil
+}
+
+// SwitchToLive makes the provider serve live data if it was created with WithManualLiveSwitch and serves
+// the bootstrap or stored configuration. It returns ErrLiveClientNotReady if the Kameleoon client hasn't
+// loaded a configuration yet, and does nothing if the provider already serves live data.
+func (p *kameleoonProvider) SwitchToLive() error {
+	bc, ok := p.client.(*bootstrapClient)
+	if !ok || bc.isLive() {
+		return nil
+	}
+	if !bc.isLiveLoaded() {
+		return ErrLiveClientNotReady
+	}
+	bc.switchToLive()
+	return nil
+}
+
+// waitConfiguration blocks until the client has loaded a configuration and reports whether it has, false
+// if stop is closed first. If WaitInit fails, the Kameleoon client keeps polling for the configuration but
+// WaitInit keeps returning the error, so the feature list is checked with an increasing delay instead.
+func waitConfiguration(client kameleoon.KameleoonClient, stop <-chan struct{}, retry, maxRetry time.Duration) bool {
+	err := client.WaitInit()
+	if err == nil {
+		return true
+	}
+	logging.Warning("The Kameleoon client failed to load the configuration, waiting for the next poll: %s", err)
+	for {
+		select {
+		case <-stop:
+			return false
+		case <-time.After(retry):
+		}
+		if len(client.GetFeatureList()) > 0 {
+			return true
+		}
+		if retry *= 2; retry > maxRetry {
+			retry = maxRetry
+		}
+	}
+}
+
+// bootstrapClient serves a KameleoonClient from a local configuration until the live client is initialized.
+type bootstrapClient struct {
+	local *localClient
+	live  kameleoon.KameleoonClient
+	// stale is set if the local configuration is a stored copy of a former remote one.
+	stale bool
+	// manual disables the switch to the live client once it's initialized, see WithManualLiveSwitch.
+	manual bool
+
+	retryInterval    time.Duration
+	maxRetryInterval time.Duration
+	stop             chan struct{}
+	stopOnce         sync.Once
+
+	switched   atomic.Value // bool
+	liveLoaded atomic.Value // bool
+	mu         sync.Mutex
+	handler    func()
+	// replay holds the data calls made on the local client, replayed to the live client on switch.
+	// The lock is held while recording and replaying, so no call is lost or reordered by the switch.
+	replayMu sync.Mutex
+	replay   []func(kameleoon.KameleoonClient) error
+}
+
+// newBootstrapClient creates a new instance of bootstrapClient and starts waiting for the live client.
+func newBootstrapClient(
+	local *localClient, live kameleoon.KameleoonClient, stale bool, options *providerOptions,
+) *bootstrapClient {
+	c := &bootstrapClient{
+		local:            local,
+		live:             live,
+		stale:            stale,
+		manual:           options.manualLiveSwitch,
+		retryInterval:    options.liveRetryInterval,
+		maxRetryInterval: liveMaxRetryInterval,
+		stop:             make(chan struct{}),
+	}
+	c.switched.Store(false)
+	c.liveLoaded.Store(false)
+	live.OnUpdateConfiguration(c.onUpdateConfiguration)
+	go c.waitLive()
+	return c
+}
+
+// waitLive switches to the live client once it has loaded a configuration, unless the switch is manual.
+func (c *bootstrapClient) waitLive() {
+	if !waitConfiguration(c.live, c.stop, c.retryInterval, c.maxRetryInterval) {
+		return
+	}
+	c.liveLoaded.Store(true)
+	if c.manual {
+		logging.Info("The Kameleoon client loaded the configuration, call SwitchToLive to serve live data")
+		return
+	}
+	c.switchToLive()
+}
+
+// switchToLive replays the data recorded on the local client to the live one and switches to it.
+func (c *bootstrapClient) switchToLive() {
+	c.replayMu.Lock()
+	if c.isLive() {
+		c.replayMu.Unlock()
+		return
+	}
+	for _, call := range c.replay {
+		if err := call(c.live); err != nil {
+			logging.Warning("Failed to replay the visitor data added before the switch to live data: %s", err)
+		}
+	}
+	c.replay = nil
+	c.switched.Store(true)
+	c.replayMu.Unlock()
+	c.local.close()
+	c.onUpdateConfiguration()
+}
+
+// record calls the local client and records the call for the replay until the client switches to live data,
+// then it calls the live client.
+func (c *bootstrapClient) record(call func(kameleoon.KameleoonClient) error) error {
+	if c.isLive() {
+		return call(c.live)
+	}
+	c.replayMu.Lock()
+	defer c.replayMu.Unlock()
+	if c.isLive() {
+		return call(c.live)
+	}
+	err := call(c.local)
+	if err != nil {
+		return err
+	}
+	if len(c.replay) < maxReplayedCalls {
+		c.replay = append(c.replay, call)
+	} else if len(c.replay) == maxReplayedCalls {
+		logging.Warning("Too much visitor data added before the switch to live data, the extra data isn't replayed")
+	}
+	return nil
+}
+
+// close stops waiting for the live client and releases the local one, the live one is released by
+// KameleoonClientFactory.
+func (c *bootstrapClient) close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	c.local.close()
+}
+
+// isLive reports whether the client has switched to live data.
+func (c *bootstrapClient) isLive() bool {
+	return c.switched.Load().(bool)
+}
+
+// isLiveLoaded reports whether the live client has loaded a configuration.
+func (c *bootstrapClient) isLiveLoaded() bool {
+	return c.liveLoaded.Load().(bool)
+}
+
+func (c *bootstrapClient) current() kameleoon.KameleoonClient {
+	if c.isLive() {
+		return c.live
+	}
+	return c.local
+}
+
+func (c *bootstrapClient) onUpdateConfiguration() {
+	c.mu.Lock()
+	handler := c.handler
+	c.mu.Unlock()
+	if handler != nil {
+		handler()
+	}
+}
+
+// WaitInit returns immediately, the local configuration is already loaded.
+func (c *bootstrapClient) WaitInit() error {
+	return nil
+}
+
+func (c *bootstrapClient) GetVisitorCode(
+	request *fasthttp.Request, response *fasthttp.Response, defaultVisitorCode ...string,
+) (string, error) {
+	return c.current().GetVisitorCode(request, response, defaultVisitorCode...)
+}
+
+// SetLegalConsent sets the legal consent, it's replayed to the live client on switch.
+func (c *bootstrapClient) SetLegalConsent(visitorCode string, consent bool, response ...*fasthttp.Response) error {
+	if len(response) > 0 {
+		// The response is written once, on the client serving the call.
+		return c.current().SetLegalConsent(visitorCode, consent, response...)
+	}
+	return c.record(func(client kameleoon.KameleoonClient) error {
+		return client.SetLegalConsent(visitorCode, consent)
+	})
+}
+
+// AddData adds the visitor data, it's replayed to the live client on switch.
+func (c *bootstrapClient) AddData(visitorCode string, allData ...types.Data) error {
+	return c.record(func(client kameleoon.KameleoonClient) error {
+		return client.AddData(visitorCode, allData...)
+	})
+}
+
+// TrackConversion tracks the conversion, it's replayed to the live client on switch.
+func (c *bootstrapClient) TrackConversion(visitorCode string, goalID int, isUniqueIdentifier ...bool) error {
+	return c.record(func(client kameleoon.KameleoonClient) error {
+		return client.TrackConversion(visitorCode, goalID, isUniqueIdentifier...)
+	})
+}
+
+// TrackConversionRevenue tracks the conversion, it's replayed to the live client on switch.
+func (c *bootstrapClient) TrackConversionRevenue(
+	visitorCode string, goalID int, revenue float64, isUniqueIdentifier ...bool,
+) error {
+	return c.record(func(client kameleoon.KameleoonClient) error {
+		return client.TrackConversionRevenue(visitorCode, goalID, revenue, isUniqueIdentifier...)
+	})
+}
+
+func (c *bootstrapClient) FlushVisitor(visitorCode string, isUniqueIdentifier ...bool) error {
+	return c.current().FlushVisitor(visitorCode, isUniqueIdentifier...)
+}
+
+func (c *bootstrapClient) FlushVisitorInstantly(visitorCode string) error {
+	return c.current().FlushVisitorInstantly(visitorCode)
+}
+
+func (c *bootstrapClient) FlushAll(instant ...bool) {
+	c.current().FlushAll(instant...)
+}
+
+func (c *bootstrapClient) GetFeatureVariationKey(
+	visitorCode string, featureKey string, isUniqueIdentifier ...bool,
+) (string, error) {
+	return c.current().GetFeatureVariationKey(visitorCode, featureKey, isUniqueIdentifier...)
+}
+
+func (c *bootstrapClient) GetFeatureVariable(
+	visitorCode string, featureKey string, variableKey string, isUniqueIdentifier ...bool,
+) (interface{}, error) {
+	return c.current().GetFeatureVariable(visitorCode, featureKey, variableKey, isUniqueIdentifier...)
+}
+
+func (c *bootstrapClient) IsFeatureActive(
+	visitorCode string, featureKey string, isUniqueIdentifier ...bool,
+) (bool, error) {
+	return c.current().IsFeatureActive(visitorCode, featureKey, isUniqueIdentifier...)
+}
+
+func (c *bootstrapClient) GetFeatureVariationVariables(
+	featureKey string, variationKey string,
+) (map[string]interface{}, error) {
+	return c.current().GetFeatureVariationVariables(featureKey, variationKey)
+}
+
+func (c *bootstrapClient) GetRemoteData(key string, timeout ...time.Duration) ([]byte, error) {
+	return c.current().GetRemoteData(key, timeout...)
+}
+
+func (c *bootstrapClient) GetVisitorWarehouseAudience(
+	params kameleoon.VisitorWarehouseAudienceParams,
+) (*types.CustomData, error) {
+	return c.current().GetVisitorWarehouseAudience(params)
+}
+
+func (c *bootstrapClient) GetVisitorWarehouseAudienceWithOptParams(
+	visitorCode string, customDataIndex int, params ...kameleoon.VisitorWarehouseAudienceOptParams,
+) (*types.CustomData, error) {
+	return c.current().GetVisitorWarehouseAudienceWithOptParams(visitorCode, customDataIndex, params...)
+}
+
+func (c *bootstrapClient) GetRemoteVisitorData(
+	visitorCode string, addData bool, timeout ...time.Duration,
+) ([]types.Data, error) {
+	return c.current().GetRemoteVisitorData(visitorCode, addData, timeout...)
+}
+
+func (c *bootstrapClient) GetRemoteVisitorDataWithOptParams(
+	visitorCode string, addData bool, filter types.RemoteVisitorDataFilter,
+	params ...kameleoon.RemoteVisitorDataOptParams,
+) ([]types.Data, error) {
+	return c.current().GetRemoteVisitorDataWithOptParams(visitorCode, addData, filter, params...)
+}
+
+func (c *bootstrapClient) GetRemoteVisitorDataWithFilter(
+	visitorCode string, addData bool, filter types.RemoteVisitorDataFilter,
+	params ...kameleoon.RemoteVisitorDataOptParams,
+) ([]types.Data, error) {
+	return c.current().GetRemoteVisitorDataWithFilter(visitorCode, addData, filter, params...)
+}
+
+// OnUpdateConfiguration registers the handler called on configuration updates of the live client and
+// when the client switches from the local configuration to the live one. The handler is called at once
+// if the switch happened before the registration.
+func (c *bootstrapClient) OnUpdateConfiguration(handler func()) {
+	c.mu.Lock()
+	c.handler = handler
+	c.mu.Unlock()
+	if handler != nil && c.isLive() {
+		handler()
+	}
+}
+
+func (c *bootstrapClient) GetFeatureList() []string {
+	return c.current().GetFeatureList()
+}
+
+func (c *bootstrapClient) GetActiveFeatureListForVisitor(visitorCode string) ([]string, error) {
+	return c.current().GetActiveFeatureListForVisitor(visitorCode)
+}
+
+func (c *bootstrapClient) GetActiveFeatures(visitorCode string) (map[string]types.Variation, error) {
+	return c.current().GetActiveFeatures(visitorCode)
+}
+
+func (c *bootstrapClient) GetEngineTrackingCode(visitorCode string) string {
+	return c.current().GetEngineTrackingCode(visitorCode)
+}
@@ -0,0 +1,165 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestBootstrapClient_SwitchesToLiveClientWhenInitialized(t *testing.T) {
+	// Arrange
+	visitorCode := "visitor"
+	initDone := make(chan time.Time)
+	liveMock := new(MockKameleoonClient)
+	liveMock.On("OnUpdateConfiguration", mock.Anything)
+	liveMock.On("WaitInit").WaitUntil(initDone).Return(nil)
+	liveMock.On("AddData", visitorCode, mock.Anything).Return(nil)
+	liveMock.On("GetFeatureVariationKey", visitorCode, "recommendations", []bool(nil)).Return("treatment", nil)
+	liveMock.On("GetFeatureVariationVariables", "recommendations", "treatment").
+		Return(map[string]interface{}{"count": 10.0}, nil)
+
+	provider := newProvider("siteCode", newBootstrapClient(newTestLocalClient(t), liveMock, false, newProviderOptions(nil)),
+		newProviderOptions(nil))
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+
+	// Act
+	initErr := provider.Init(openfeature.EvaluationContext{})
+	beforeSwitch := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+	close(initDone)
+	var event openfeature.Event
+	select {
+	case event = <-provider.EventChannel():
+	case <-time.After(time.Second):
+	}
+	afterSwitch := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+
+	// Assert
+	assert.NoError(t, initErr)
+	assert.Equal(t, 5.0, beforeSwitch.Value)
+	assert.Equal(t, openfeature.ProviderConfigChange, event.EventType)
+	assert.Equal(t, 10.0, afterSwitch.Value)
+}
+
+func TestBootstrapClient_StaysOnLocalConfigurationUntilLiveClientLoadsOne(t *testing.T) {
+	// Arrange
+	liveMock := new(MockKameleoonClient)
+	liveMock.On("OnUpdateConfiguration", mock.Anything)
+	liveMock.On("WaitInit").Return(context.DeadlineExceeded)
+	polled := make(chan struct{})
+	liveMock.On("GetFeatureList").Run(func(mock.Arguments) { close(polled) }).Return([]string{}).Once()
+	liveMock.On("GetFeatureList").Return([]string{})
+
+	options := newProviderOptions(nil)
+	options.liveRetryInterval = time.Millisecond
+	client := newBootstrapClient(newTestLocalClient(t), liveMock, false, options)
+	defer client.close()
+	<-polled
+
+	// Act
+	variation, err := client.GetFeatureVariationKey("visitor", "recommendations")
+
+	// Assert
+	assert.False(t, client.isLive())
+	assert.NoError(t, err)
+	assert.Equal(t, "control", variation)
+	liveMock.AssertNotCalled(t, "GetFeatureVariationKey", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBootstrapClient_SwitchesToLiveClientAfterFailedInitialization(t *testing.T) {
+	// Arrange
+	liveMock := new(MockKameleoonClient)
+	liveMock.On("OnUpdateConfiguration", mock.Anything)
+	liveMock.On("WaitInit").Return(context.DeadlineExceeded)
+	liveMock.On("GetFeatureList").Return([]string{}).Once()
+	liveMock.On("GetFeatureList").Return([]string{"recommendations"})
+	liveMock.On("GetFeatureVariationKey", "visitor", "recommendations", []bool(nil)).Return("treatment", nil)
+
+	options := newProviderOptions(nil)
+	options.liveRetryInterval = time.Millisecond
+
+	// Act
+	client := newBootstrapClient(newTestLocalClient(t), liveMock, false, options)
+	defer client.close()
+	require.Eventually(t, client.isLive, time.Second, time.Millisecond)
+	variation, err := client.GetFeatureVariationKey("visitor", "recommendations")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "treatment", variation)
+}
+
+func TestBootstrapClient_ReplaysDataAddedBeforeSwitch(t *testing.T) {
+	// Arrange
+	customData := types.NewCustomData(1, "premium")
+	liveMock := new(MockKameleoonClient)
+	liveMock.On("OnUpdateConfiguration", mock.Anything)
+	liveMock.On("SetLegalConsent", "visitor", true, []*fasthttp.Response(nil)).Return(nil)
+	liveMock.On("AddData", "visitor", []types.Data{customData}).Return(nil)
+	liveMock.On("TrackConversion", "visitor", 10, []bool(nil)).Return(nil)
+	liveMock.On("TrackConversionRevenue", "visitor", 10, 5.0, []bool(nil)).Return(nil)
+	waiting := make(chan struct{})
+	initDone := make(chan struct{})
+	liveMock.On("WaitInit").Run(func(mock.Arguments) {
+		close(waiting)
+		<-initDone
+	}).Return(nil)
+	client := newBootstrapClient(newTestLocalClient(t), liveMock, false, newProviderOptions(nil))
+	defer client.close()
+	<-waiting
+
+	// Act
+	_ = client.SetLegalConsent("visitor", true)
+	_ = client.AddData("visitor", customData)
+	_ = client.TrackConversion("visitor", 10)
+	_ = client.TrackConversionRevenue("visitor", 10, 5.0)
+	callsBeforeSwitch := len(liveMock.Calls)
+	client.switchToLive()
+
+	// Assert
+	var calls []string
+	for _, call := range liveMock.Calls {
+		calls = append(calls, call.Method)
+	}
+	assert.Equal(t, 2, callsBeforeSwitch)
+	assert.Equal(t, []string{
+		"OnUpdateConfiguration", "WaitInit", "SetLegalConsent", "AddData", "TrackConversion", "TrackConversionRevenue",
+	}, calls)
+}
+
+func TestKameleoonProvider_ManualLiveSwitch(t *testing.T) {
+	// Arrange
+	visitorCode := "visitor"
+	liveMock := new(MockKameleoonClient)
+	liveMock.On("OnUpdateConfiguration", mock.Anything)
+	initDone := make(chan time.Time)
+	liveMock.On("WaitInit").WaitUntil(initDone).Return(nil)
+	liveMock.On("AddData", visitorCode, mock.Anything).Return(nil)
+	liveMock.On("GetFeatureVariationKey", visitorCode, "recommendations", []bool(nil)).Return("treatment", nil)
+	liveMock.On("GetFeatureVariationVariables", "recommendations", "treatment").
+		Return(map[string]interface{}{"count": 10.0}, nil)
+	options := newProviderOptions([]Option{WithManualLiveSwitch()})
+	client := newBootstrapClient(newTestLocalClient(t), liveMock, false, options)
+	provider := newProvider("siteCode", client, options)
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+
+	// Act
+	notLoadedErr := provider.SwitchToLive()
+	close(initDone)
+	require.Eventually(t, client.isLiveLoaded, time.Second, time.Millisecond)
+	beforeSwitch := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+	switchErr := provider.SwitchToLive()
+	afterSwitch := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+
+	// Assert
+	assert.ErrorIs(t, notLoadedErr, ErrLiveClientNotReady)
+	assert.Equal(t, 5.0, beforeSwitch.Value)
+	assert.NoError(t, switchErr)
+	assert.Equal(t, 10.0, afterSwitch.Value)
+}
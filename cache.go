@@ -0,0 +1,243 @@
+package kameleoon
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	// DefaultCacheTTL is the time-to-live of a cached evaluation if CacheConfig.TTL isn't set.
+	DefaultCacheTTL = time.Minute
+	// DefaultCacheMaxSize is the maximum number of cached evaluations if CacheConfig.MaxSize isn't set.
+	DefaultCacheMaxSize = 10000
+)
+
+// CacheConfig is used to configure the in-process evaluation cache.
+type CacheConfig struct {
+	// TTL is how long an evaluation result stays valid.
+	TTL time.Duration
+	// MaxSize is the maximum number of stored results, the least recently used one is evicted first.
+	MaxSize int
+}
+
+// WithEvaluationCache enables the in-process evaluation cache.
+//
+// Results are cached by visitor code, flag key, variable key and a fingerprint of the evaluation context.
+// The cache is cleared when the Kameleoon client is notified of a configuration update, which happens only
+// in the real-time (streaming) update mode. In the default polling mode the client isn't notified, so a cached
+// result may outlive a configuration change until its TTL expires. A cache hit doesn't call the KameleoonClient,
+// so neither the context data nor the exposure are sent again for it.
+func WithEvaluationCache(config CacheConfig) Option {
+	return func(o *providerOptions) {
+		o.cacheConfig = &config
+	}
+}
+
+// cacheEntry is a single cached evaluation result.
+type cacheEntry struct {
+	key       string
+	res       resolution
+	expiresAt time.Time
+}
+
+// evaluationCache is a size-bounded LRU cache of evaluation results with TTL.
+type evaluationCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[string]*list.Element
+	lru     *list.List
+	now     func() time.Time
+}
+
+// newEvaluationCache creates a new instance of evaluationCache, applying defaults for unset values.
+func newEvaluationCache(config CacheConfig) *evaluationCache {
+	if config.TTL <= 0 {
+		config.TTL = DefaultCacheTTL
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultCacheMaxSize
+	}
+	return &evaluationCache{
+		ttl:     config.TTL,
+		maxSize: config.MaxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+}
+
+// get returns the entry stored by the key if it exists and isn't expired.
+func (c *evaluationCache) get(key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.removeElement(element)
+		return cacheEntry{}, false
+	}
+	c.lru.MoveToFront(element)
+	return *entry, true
+}
+
+// set stores the resolution by the key, evicting the least recently used entry if the cache is full.
+func (c *evaluationCache) set(key string, res resolution) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*cacheEntry)
+		entry.res, entry.expiresAt = res, expiresAt
+		c.lru.MoveToFront(element)
+		return
+	}
+	for c.lru.Len() >= c.maxSize {
+		c.removeElement(c.lru.Back())
+	}
+	entry := &cacheEntry{key: key, res: res, expiresAt: expiresAt}
+	c.entries[key] = c.lru.PushFront(entry)
+}
+
+// clear removes all entries from the cache.
+func (c *evaluationCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// len returns the number of stored entries.
+func (c *evaluationCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *evaluationCache) removeElement(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
+
+// cachingResolver serves evaluations from evaluationCache and delegates misses to the wrapped resolver.
+type cachingResolver struct {
+	resolver resolver
+	cache    *evaluationCache
+}
+
+// newCachingResolver creates a new instance of cachingResolver.
+func newCachingResolver(resolver resolver, cache *evaluationCache) *cachingResolver {
+	return &cachingResolver{
+		resolver: resolver,
+		cache:    cache,
+	}
+}
+
+// Resolve returns a cached result if present, otherwise resolves it by the wrapped resolver.
+func (r *cachingResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *cachingResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	visitorCode, ok := getTargetingKey(evalContext)
+	if !ok {
+		return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+	}
+	key := makeCacheKey(visitorCode, flag, defaultValue, isUntracked(ctx), evalContext)
+	if entry, ok := r.cache.get(key); ok {
+		res := entry.res
+		// A disabled flag keeps its reason, so callers can still tell it apart from a served variation.
+		if res.reason != openfeature.DisabledReason {
+			res.reason = openfeature.CachedReason
+		}
+		return res
+	}
+	res := resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+	if res.err == nil {
+		r.cache.set(key, res)
+	}
+	return res
+}
+
+// makeCacheKey builds the cache key from the visitor code, flag key, variable key, requested type, whether
+// the evaluation is untracked and a fingerprint of the evaluation context. The strings are quoted, so distinct
+// parts never build the same key, and the type of a nil default value, e.g. of an object evaluation, is "<nil>".
+// An untracked result is never served to a tracked evaluation, which would then track no exposure.
+func makeCacheKey(
+	visitorCode string, flag string, defaultValue interface{}, untracked bool, evalContext openfeature.FlattenedContext,
+) string {
+	variableKey, _ := evalContext["variableKey"].(string)
+	return fmt.Sprintf("%q%q%q%T%t:%x",
+		visitorCode, flag, variableKey, defaultValue, untracked, contextFingerprint(evalContext))
+}
+
+// contextFingerprint hashes the evaluation context. Keys are hashed in sorted order, so the result doesn't
+// depend on the map iteration order.
+func contextFingerprint(evalContext openfeature.FlattenedContext) uint64 {
+	h := fnv.New64a()
+	writeFingerprint(h, map[string]interface{}(evalContext))
+	return h.Sum64()
+}
+
+// writeFingerprint writes a deterministic representation of the value to the hash. Strings are length-prefixed,
+// so a value containing the delimiters can't be confused with several values.
+func writeFingerprint(h hash.Hash64, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		writeFingerprintString(h, v)
+	case int:
+		_, _ = h.Write([]byte(strconv.Itoa(v)))
+	case int64:
+		_, _ = h.Write([]byte(strconv.FormatInt(v, 10)))
+	case float64:
+		_, _ = h.Write([]byte(strconv.FormatFloat(v, 'g', -1, 64)))
+	case bool:
+		_, _ = h.Write([]byte(strconv.FormatBool(v)))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		_, _ = h.Write([]byte{'{'})
+		for _, k := range keys {
+			writeFingerprintString(h, k)
+			_, _ = h.Write([]byte{':'})
+			writeFingerprint(h, v[k])
+			_, _ = h.Write([]byte{','})
+		}
+		_, _ = h.Write([]byte{'}'})
+	case []map[string]interface{}:
+		_, _ = h.Write([]byte{'['})
+		for _, item := range v {
+			writeFingerprint(h, item)
+			_, _ = h.Write([]byte{','})
+		}
+		_, _ = h.Write([]byte{']'})
+	default:
+		writeFingerprintString(h, fmt.Sprintf("%T%v", v, v))
+	}
+}
+
+// writeFingerprintString writes the length of the string, then the string to the hash.
+func writeFingerprintString(h hash.Hash64, s string) {
+	_, _ = h.Write([]byte(strconv.Itoa(len(s))))
+	_, _ = h.Write([]byte{'#'})
+	_, _ = h.Write([]byte(s))
+}
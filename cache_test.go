@@ -0,0 +1,304 @@
+package kameleoon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/errs"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/Kameleoon/client-go/v3/utils"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEvaluationCache_ExpiresEntriesAfterTTL(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	cache := newEvaluationCache(CacheConfig{TTL: time.Second})
+	cache.now = func() time.Time { return now }
+	cache.set("key", resolution{value: 10, variant: "on"})
+
+	// Act
+	entry, okBefore := cache.get("key")
+	now = now.Add(time.Second)
+	_, okAfter := cache.get("key")
+
+	// Assert
+	assert.True(t, okBefore)
+	assert.Equal(t, 10, entry.res.value)
+	assert.Equal(t, "on", entry.res.variant)
+	assert.False(t, okAfter)
+	assert.Equal(t, 0, cache.len())
+}
+
+func TestEvaluationCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	// Arrange
+	cache := newEvaluationCache(CacheConfig{MaxSize: 2})
+	cache.set("first", resolution{value: 1})
+	cache.set("second", resolution{value: 2})
+	cache.get("first")
+
+	// Act
+	cache.set("third", resolution{value: 3})
+
+	// Assert
+	_, okFirst := cache.get("first")
+	_, okSecond := cache.get("second")
+	_, okThird := cache.get("third")
+	assert.True(t, okFirst)
+	assert.False(t, okSecond)
+	assert.True(t, okThird)
+	assert.Equal(t, 2, cache.len())
+}
+
+func TestCachingResolver_SecondEvaluationIsServedFromCache(t *testing.T) {
+	// Arrange
+	flagKey := "testFlag"
+	visitorCode := "testVisitor"
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("AddData", visitorCode, []types.Data(nil)).Return(nil).Once()
+	clientMock.On("GetFeatureVariationKey", visitorCode, flagKey, []bool(nil)).Return("on", nil).Once()
+	clientMock.On("GetFeatureVariationVariables", flagKey, "on").
+		Return(map[string]interface{}{"k": 10}, nil).Once()
+
+	resolver := newCachingResolver(newKameleoonResolver(clientMock), newEvaluationCache(CacheConfig{}))
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+
+	// Act
+	first := resolver.resolveDetail(context.Background(), flagKey, 0, evalContext)
+	second := resolver.resolveDetail(context.Background(), flagKey, 0, evalContext)
+
+	// Assert
+	assert.Equal(t, 10, first.value)
+	assert.Empty(t, first.reason)
+	assert.Equal(t, 10, second.value)
+	assert.Equal(t, "on", second.variant)
+	assert.Equal(t, openfeature.CachedReason, second.reason)
+	clientMock.AssertExpectations(t)
+}
+
+func TestCachingResolver_CachedDisabledFlagKeepsDisabledReason(t *testing.T) {
+	// Arrange
+	flagKey := "testFlag"
+	visitorCode := "testVisitor"
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("AddData", visitorCode, []types.Data(nil)).Return(nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, flagKey, []bool(nil)).
+		Return("", errs.NewFeatureEnvironmentDisabled(flagKey, "production"))
+
+	resolver := newCachingResolver(newKameleoonResolver(clientMock), newEvaluationCache(CacheConfig{}))
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+
+	// Act
+	first := resolver.resolveDetail(context.Background(), flagKey, 0, evalContext)
+	cached := resolver.resolveDetail(context.Background(), flagKey, 0, evalContext)
+
+	// Assert
+	assert.Equal(t, first, cached)
+	assert.Equal(t, openfeature.DisabledReason, cached.reason)
+	clientMock.AssertNumberOfCalls(t, "GetFeatureVariationKey", 1)
+}
+
+func TestCachingResolver_DifferentContextIsNotServedFromCache(t *testing.T) {
+	// Arrange
+	flagKey := "testFlag"
+	visitorCode := "testVisitor"
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("AddData", visitorCode, mock.Anything).Return(nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, flagKey, []bool(nil)).Return("on", nil)
+	clientMock.On("GetFeatureVariationVariables", flagKey, "on").
+		Return(map[string]interface{}{"k": 10, "v": "str"}, nil)
+
+	resolver := newCachingResolver(newKameleoonResolver(clientMock), newEvaluationCache(CacheConfig{}))
+
+	// Act
+	resolver.resolveDetail(context.Background(), flagKey, 0, openfeature.FlattenedContext{
+		"targetingKey": visitorCode,
+	})
+	withVariableKey := resolver.resolveDetail(context.Background(), flagKey, "", openfeature.FlattenedContext{
+		"targetingKey": visitorCode, "variableKey": "v",
+	})
+	withCustomData := resolver.resolveDetail(context.Background(), flagKey, 0, openfeature.FlattenedContext{
+		"targetingKey": visitorCode,
+		Data.Type.CustomData: map[string]interface{}{
+			Data.CustomDataType.Index: 1, Data.CustomDataType.Values: "10",
+		},
+	})
+
+	// Assert
+	assert.Equal(t, "str", withVariableKey.value)
+	assert.Empty(t, withVariableKey.reason)
+	assert.Empty(t, withCustomData.reason)
+	clientMock.AssertNumberOfCalls(t, "GetFeatureVariationKey", 3)
+}
+
+func TestCachingResolver_UntrackedResultIsNotServedToTrackedEvaluation(t *testing.T) {
+	// Arrange
+	flagKey := "testFlag"
+	visitorCode := "testVisitor"
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("AddData", visitorCode, []types.Data(nil)).Return(nil)
+	clientMock.On("GetFeatureVariationVariables", flagKey, "off").Return(map[string]interface{}{}, nil)
+	clientMock.On("GetActiveFeatures", visitorCode).Return(map[string]types.Variation{flagKey: {Key: "on"}}, nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, flagKey, []bool(nil)).Return("on", nil)
+	clientMock.On("GetFeatureVariationVariables", flagKey, "on").Return(map[string]interface{}{"k": 10}, nil)
+
+	resolver := newCachingResolver(newKameleoonResolver(clientMock), newEvaluationCache(CacheConfig{}))
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+	untrackedCtx, _ := withoutTracking(context.Background(), nil)
+
+	// Act
+	untracked := resolver.resolveDetail(untrackedCtx, flagKey, 0, evalContext)
+	tracked := resolver.resolveDetail(context.Background(), flagKey, 0, evalContext)
+
+	// Assert
+	assert.Equal(t, 10, untracked.value)
+	assert.Equal(t, 10, tracked.value)
+	assert.Empty(t, tracked.reason)
+	clientMock.AssertNumberOfCalls(t, "GetFeatureVariationKey", 1)
+}
+
+func TestContextFingerprint_DelimitsStrings(t *testing.T) {
+	// Arrange
+	joined := openfeature.FlattenedContext{"a": "b,c:d"}
+	split := openfeature.FlattenedContext{"a": "b", "c": "d"}
+
+	// Act
+	joinedFingerprint := contextFingerprint(joined)
+	splitFingerprint := contextFingerprint(split)
+
+	// Assert
+	assert.NotEqual(t, joinedFingerprint, splitFingerprint)
+}
+
+func TestKameleoonProvider_CachedObjectEvaluationWithNilDefault(t *testing.T) {
+	// Arrange
+	flagKey := "testFlag"
+	visitorCode := "testVisitor"
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything)
+	clientMock.On("AddData", visitorCode, []types.Data(nil)).Return(nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, flagKey, []bool(nil)).Return("off", nil)
+	clientMock.On("GetFeatureVariationVariables", flagKey, "off").Return(map[string]interface{}{}, nil)
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock, WithEvaluationCache(CacheConfig{}))
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+
+	// Act
+	first := provider.ObjectEvaluation(context.Background(), flagKey, nil, evalContext)
+	second := provider.ObjectEvaluation(context.Background(), flagKey, nil, evalContext)
+
+	// Assert
+	assert.NoError(t, first.Error())
+	assert.Nil(t, first.Value)
+	assert.Equal(t, "off", second.Variant)
+	assert.Equal(t, openfeature.CachedReason, second.Reason)
+	clientMock.AssertNumberOfCalls(t, "GetFeatureVariationKey", 1)
+}
+
+func TestCachingResolver_ErrorsAreNotCached(t *testing.T) {
+	// Arrange
+	flagKey := "testFlag"
+	visitorCode := "testVisitor"
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("AddData", visitorCode, []types.Data(nil)).Return(nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, flagKey, []bool(nil)).Return("on", nil)
+	clientMock.On("GetFeatureVariationVariables", flagKey, "on").
+		Return(map[string]interface{}{}, nil)
+
+	resolver := newCachingResolver(newKameleoonResolver(clientMock), newEvaluationCache(CacheConfig{}))
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+
+	// Act
+	resolver.resolveDetail(context.Background(), flagKey, 0, evalContext)
+	second := resolver.resolveDetail(context.Background(), flagKey, 0, evalContext)
+
+	// Assert
+	assert.NotNil(t, second.err)
+	clientMock.AssertNumberOfCalls(t, "GetFeatureVariationKey", 2)
+}
+
+func TestKameleoonProvider_ConfigurationUpdateClearsCache(t *testing.T) {
+	// Arrange
+	flagKey := "testFlag"
+	visitorCode := "testVisitor"
+	var onUpdate func()
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything).Run(func(args mock.Arguments) {
+		onUpdate = args.Get(0).(func())
+	})
+	clientMock.On("AddData", visitorCode, []types.Data(nil)).Return(nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, flagKey, []bool(nil)).Return("on", nil)
+	clientMock.On("GetFeatureVariationVariables", flagKey, "on").
+		Return(map[string]interface{}{"k": int64(10)}, nil)
+
+	provider := newProvider("siteCode", clientMock, newProviderOptions([]Option{WithEvaluationCache(CacheConfig{})}))
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+
+	// Act
+	first := provider.IntEvaluation(context.Background(), flagKey, 0, evalContext)
+	cached := provider.IntEvaluation(context.Background(), flagKey, 0, evalContext)
+	onUpdate()
+	afterUpdate := provider.IntEvaluation(context.Background(), flagKey, 0, evalContext)
+
+	// Assert
+	assert.Equal(t, int64(10), first.Value)
+	assert.Equal(t, openfeature.CachedReason, cached.Reason)
+	assert.Empty(t, afterUpdate.Reason)
+	clientMock.AssertNumberOfCalls(t, "GetFeatureVariationKey", 2)
+}
+
+// benchmarkClient is a KameleoonClient which only does the rule bucketing of the SDK, so the benchmarks
+// measure the resolver path without network, targeting or logging.
+type benchmarkClient struct {
+	kameleoon.KameleoonClient
+	rules     int
+	variables map[string]interface{}
+}
+
+func (c *benchmarkClient) AddData(string, ...types.Data) error {
+	return nil
+}
+
+func (c *benchmarkClient) GetFeatureVariationKey(visitorCode string, _ string, _ ...bool) (string, error) {
+	for rule := 0; rule < c.rules; rule++ {
+		if utils.GetHashDoubleRule(visitorCode, rule, 0) <= 0.5 {
+			utils.GetHashDoubleRule(visitorCode, rule+c.rules, 0)
+			break
+		}
+	}
+	return "on", nil
+}
+
+func (c *benchmarkClient) GetFeatureVariationVariables(string, string) (map[string]interface{}, error) {
+	return c.variables, nil
+}
+
+func benchmarkResolver(b *testing.B, r resolver) {
+	evalContexts := make([]openfeature.FlattenedContext, 100)
+	for i := range evalContexts {
+		evalContexts[i] = openfeature.FlattenedContext{
+			"targetingKey": fmt.Sprintf("visitor%d", i),
+			Data.Type.CustomData: map[string]interface{}{
+				Data.CustomDataType.Index: 1, Data.CustomDataType.Values: "10",
+			},
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Resolve(context.Background(), "flagKey", int64(0), evalContexts[i%len(evalContexts)])
+	}
+}
+
+func BenchmarkKameleoonResolver_Resolve(b *testing.B) {
+	client := &benchmarkClient{rules: 3, variables: map[string]interface{}{"k": int64(10)}}
+	benchmarkResolver(b, newKameleoonResolver(client))
+}
+
+func BenchmarkCachingResolver_Resolve(b *testing.B) {
+	client := &benchmarkClient{rules: 3, variables: map[string]interface{}{"k": int64(10)}}
+	benchmarkResolver(b, newCachingResolver(newKameleoonResolver(client), newEvaluationCache(CacheConfig{})))
+}
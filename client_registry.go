@@ -0,0 +1,137 @@
+package kameleoon
+
+import (
+	"sync"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+)
+
+// factoryClients counts the providers using each client created by KameleoonClientFactory.
+var factoryClients = newClientRegistry(kameleoon.KameleoonClientFactory.Create, kameleoon.KameleoonClientFactory.Forget)
+
+// clientRegistry counts the providers sharing a client per site code. KameleoonClientFactory returns the same
+// client for the same site code, so the client is forgotten only when the last provider using it releases it.
+//
+// The Kameleoon client keeps a single configuration update handler, so the registry registers one handler
+// per client which calls the handlers of all providers sharing it.
+type clientRegistry struct {
+	mu      sync.Mutex
+	clients map[string]*registeredClient
+	create  func(siteCode string, config *kameleoon.KameleoonClientConfig) (kameleoon.KameleoonClient, error)
+	forget  func(siteCode string)
+}
+
+// registeredClient is a client of the registry with its owners and their configuration update handlers.
+type registeredClient struct {
+	client   kameleoon.KameleoonClient
+	owners   int
+	handlers map[*sharedClient]func()
+}
+
+// sharedClient is the client of one provider sharing a registered client. It subscribes the configuration
+// update handler of the provider to the registry instead of replacing the handler of the client.
+type sharedClient struct {
+	kameleoon.KameleoonClient
+	siteCode string
+	registry *clientRegistry
+}
+
+// newClientRegistry creates a new instance of clientRegistry creating clients by create and calling forget
+// when a client has no owner left.
+func newClientRegistry(
+	create func(siteCode string, config *kameleoon.KameleoonClientConfig) (kameleoon.KameleoonClient, error),
+	forget func(siteCode string),
+) *clientRegistry {
+	return &clientRegistry{
+		clients: make(map[string]*registeredClient),
+		create:  create,
+		forget:  forget,
+	}
+}
+
+// acquire creates the client of the site code, or gets the existing one, and registers a new owner of it.
+// Both happen under the registry lock, so a concurrent release can't forget the client in between.
+func (r *clientRegistry) acquire(
+	siteCode string, config *kameleoon.KameleoonClientConfig,
+) (*sharedClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	client, err := r.create(siteCode, config)
+	if err != nil {
+		return nil, err
+	}
+	registered, ok := r.clients[siteCode]
+	if !ok || registered.client != client {
+		registered = &registeredClient{client: client, handlers: make(map[*sharedClient]func())}
+		r.clients[siteCode] = registered
+		client.OnUpdateConfiguration(func() { r.notify(siteCode) })
+	}
+	registered.owners++
+	return &sharedClient{KameleoonClient: client, siteCode: siteCode, registry: r}, nil
+}
+
+// release unregisters the owner of the shared client and forgets the client if it was the last one.
+func (r *clientRegistry) release(shared *sharedClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	registered, ok := r.clients[shared.siteCode]
+	if !ok || registered.client != shared.KameleoonClient {
+		return
+	}
+	delete(registered.handlers, shared)
+	registered.owners--
+	if registered.owners == 0 {
+		delete(r.clients, shared.siteCode)
+		r.forget(shared.siteCode)
+	}
+}
+
+// subscribe sets the configuration update handler of the shared client, nil removes it.
+func (r *clientRegistry) subscribe(shared *sharedClient, handler func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	registered, ok := r.clients[shared.siteCode]
+	if !ok || registered.client != shared.KameleoonClient {
+		return
+	}
+	if handler == nil {
+		delete(registered.handlers, shared)
+	} else {
+		registered.handlers[shared] = handler
+	}
+}
+
+// notify calls the configuration update handlers of all owners of the client of the site code.
+func (r *clientRegistry) notify(siteCode string) {
+	r.mu.Lock()
+	var handlers []func()
+	if registered, ok := r.clients[siteCode]; ok {
+		for _, handler := range registered.handlers {
+			handlers = append(handlers, handler)
+		}
+	}
+	r.mu.Unlock()
+	for _, handler := range handlers {
+		handler()
+	}
+}
+
+// owned returns the number of providers using the client of the site code.
+func (r *clientRegistry) owned(siteCode string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if registered, ok := r.clients[siteCode]; ok {
+		return registered.owners
+	}
+	return 0
+}
+
+// OnUpdateConfiguration subscribes the handler to the configuration updates of the shared client.
+func (c *sharedClient) OnUpdateConfiguration(handler func()) {
+	c.registry.subscribe(c, handler)
+}
+
+// release unregisters the shared client from its registry.
+func (c *sharedClient) release() {
+	c.registry.release(c)
+}
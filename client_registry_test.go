@@ -0,0 +1,194 @@
+package kameleoon
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// forgetRecorder records the site codes forgotten by a clientRegistry.
+type forgetRecorder struct {
+	mu        sync.Mutex
+	forgotten []string
+}
+
+func (r *forgetRecorder) forget(siteCode string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.forgotten = append(r.forgotten, siteCode)
+}
+
+func (r *forgetRecorder) calls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.forgotten...)
+}
+
+// newTestClientRegistry creates a clientRegistry whose clients are all the given one.
+func newTestClientRegistry(client kameleoon.KameleoonClient, forget func(siteCode string)) *clientRegistry {
+	return newClientRegistry(func(string, *kameleoon.KameleoonClientConfig) (kameleoon.KameleoonClient, error) {
+		return client, nil
+	}, forget)
+}
+
+// newOwnedTestProvider creates a provider owning its client in the registry, as NewKameleoonProvider does.
+func newOwnedTestProvider(registry *clientRegistry, siteCode string) *kameleoonProvider {
+	client, err := registry.acquire(siteCode, nil)
+	if err != nil {
+		panic(err)
+	}
+	provider := NewKameleoonProviderWithClient(siteCode, client)
+	provider.shared = client
+	return provider
+}
+
+func TestClientRegistry_ForgetsClientWhenLastOwnerReleases(t *testing.T) {
+	// Arrange
+	recorder := &forgetRecorder{}
+	registry := newTestClientRegistry(kameleoontest.NewClient(), recorder.forget)
+	first, _ := registry.acquire("siteCode", nil)
+	second, _ := registry.acquire("siteCode", nil)
+
+	// Act
+	first.release()
+	afterFirst := recorder.calls()
+	second.release()
+	second.release()
+
+	// Assert
+	assert.Empty(t, afterFirst)
+	assert.Equal(t, []string{"siteCode"}, recorder.calls())
+	assert.Equal(t, 0, registry.owned("siteCode"))
+}
+
+func TestKameleoonProvider_Shutdown_ReleasesClientOnce(t *testing.T) {
+	// Arrange
+	recorder := &forgetRecorder{}
+	registry := newTestClientRegistry(kameleoontest.NewClient(), recorder.forget)
+	first := newOwnedTestProvider(registry, "siteCode")
+	second := newOwnedTestProvider(registry, "siteCode")
+
+	// Act
+	first.Shutdown()
+	first.Shutdown()
+	afterFirst := recorder.calls()
+	second.Shutdown()
+
+	// Assert
+	assert.Empty(t, afterFirst)
+	assert.Equal(t, []string{"siteCode"}, recorder.calls())
+}
+
+func TestNewKameleoonProviderWithClient_Shutdown_NeverForgetsClient(t *testing.T) {
+	// Arrange
+	provider := NewKameleoonProviderWithClient("siteCode", kameleoontest.NewClient())
+
+	// Act
+	provider.Shutdown()
+
+	// Assert
+	assert.Nil(t, provider.shared)
+}
+
+func TestKameleoonProvider_SetNamedProvider_ReplacingOneDomainKeepsSharedClient(t *testing.T) {
+	// Arrange
+	recorder := &forgetRecorder{}
+	registry := newTestClientRegistry(kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "banner",
+		DefaultVariation: "on",
+		Variations:       []kameleoontest.Variation{{Key: "on", Variables: map[string]interface{}{"title": "Sale"}}},
+	}), recorder.forget)
+	web := newOwnedTestProvider(registry, "siteCode")
+	api := newOwnedTestProvider(registry, "siteCode")
+	require.NoError(t, openfeature.SetNamedProviderAndWait("registry-web", web))
+	require.NoError(t, openfeature.SetNamedProviderAndWait("registry-api", api))
+	defer func() { _ = openfeature.SetNamedProviderAndWait("registry-api", openfeature.NoopProvider{}) }()
+
+	// Act
+	require.NoError(t, openfeature.SetNamedProviderAndWait("registry-web", openfeature.NoopProvider{}))
+	require.Eventually(t, func() bool { return registry.owned("siteCode") == 1 }, time.Second, time.Millisecond)
+	title, err := openfeature.NewClient("registry-api").StringValue(context.Background(), "banner", "",
+		openfeature.NewEvaluationContext("visitor", nil))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Sale", title)
+	assert.Empty(t, recorder.calls())
+}
+
+func TestKameleoonProvider_ShutdownOfProviderInSeveralDomains_ReleasesClientOnce(t *testing.T) {
+	// Arrange
+	recorder := &forgetRecorder{}
+	registry := newTestClientRegistry(kameleoontest.NewClient(), recorder.forget)
+	shared := newOwnedTestProvider(registry, "siteCode")
+	other := newOwnedTestProvider(registry, "siteCode")
+	require.NoError(t, openfeature.SetNamedProviderAndWait("registry-shared-a", shared))
+	require.NoError(t, openfeature.SetNamedProviderAndWait("registry-shared-b", shared))
+
+	// Act
+	openfeature.Shutdown()
+	ownedAfterShutdown := registry.owned("siteCode")
+	other.Shutdown()
+
+	// Assert
+	assert.Equal(t, 1, ownedAfterShutdown)
+	assert.Equal(t, []string{"siteCode"}, recorder.calls())
+}
+
+func TestNewKameleoonProvider_ProvidersOfSameSiteCodeShareClientUntilLastShutdown(t *testing.T) {
+	// Arrange
+	siteCode := "sharedSiteCode"
+	configuration, err := os.ReadFile(testConfigurationPath)
+	require.NoError(t, err)
+	server := kameleoontest.NewServer(siteCode, configuration)
+	defer server.Close()
+	config := server.ClientConfig()
+
+	first, err := NewKameleoonProvider(siteCode, config)
+	require.NoError(t, err)
+	second, err := NewKameleoonProvider(siteCode, config)
+	require.NoError(t, err)
+	defer second.Shutdown()
+
+	// Act
+	first.Shutdown()
+	client, err := kameleoon.KameleoonClientFactory.Create(siteCode, config)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Same(t, second.GetClient(), client)
+	assert.Equal(t, 1, factoryClients.owned(siteCode))
+}
+
+func TestClientRegistry_NotifiesAllProvidersSharingClient(t *testing.T) {
+	// Arrange
+	client := kameleoontest.NewClient()
+	registry := newTestClientRegistry(client, (&forgetRecorder{}).forget)
+	first := newOwnedTestProvider(registry, "siteCode")
+	second := newOwnedTestProvider(registry, "siteCode")
+	released := newOwnedTestProvider(registry, "siteCode")
+	released.shared.release()
+
+	// Act
+	client.SetFeatures()
+
+	// Assert
+	for _, provider := range []*kameleoonProvider{first, second} {
+		select {
+		case event := <-provider.EventChannel():
+			assert.Equal(t, openfeature.ProviderConfigChange, event.EventType)
+		case <-time.After(time.Second):
+			assert.Fail(t, "no configuration change notified")
+		}
+	}
+	assert.Empty(t, released.EventChannel())
+	assert.Equal(t, 2, registry.owned("siteCode"))
+}
@@ -0,0 +1,253 @@
+package kameleoon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/configuration"
+	"github.com/Kameleoon/client-go/v3/logging"
+	"github.com/Kameleoon/client-go/v3/network"
+	"github.com/Kameleoon/client-go/v3/utils"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// ConfigurationStore persists the last good Kameleoon configuration, so the provider can serve flags from it
+// when Kameleoon is unavailable on startup. It can be backed by a file, Redis or any other shared storage.
+type ConfigurationStore interface {
+	// Load returns the configuration stored for the site code, or nil without an error if there is none.
+	Load(siteCode string) ([]byte, error)
+	// Save stores the configuration for the site code, replacing the previous one.
+	Save(siteCode string, configuration []byte) error
+}
+
+// WithConfigurationStore makes the provider persist the last good configuration to the given store.
+//
+// On startup the provider loads the stored configuration and serves flags from it with the STALE state and
+// the CACHED reason until the Kameleoon client loads a fresh configuration. The store takes precedence over
+// WithBootstrapConfiguration, whose file is used only if the store is empty. The stored configuration is
+// refreshed on every update notified by the Kameleoon client and at its refresh interval.
+func WithConfigurationStore(store ConfigurationStore) Option {
+	return func(o *providerOptions) {
+		o.configStore = store
+	}
+}
+
+// fileConfigurationStore is a ConfigurationStore keeping one file per site code in a directory.
+type fileConfigurationStore struct {
+	dir string
+}
+
+// NewFileConfigurationStore creates a new instance of ConfigurationStore which keeps configurations
+// in files of the given directory.
+func NewFileConfigurationStore(dir string) ConfigurationStore {
+	return &fileConfigurationStore{dir: dir}
+}
+
+func (s *fileConfigurationStore) path(siteCode string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("kameleoon-%s.json", siteCode))
+}
+
+// Load reads the configuration file of the site code.
+func (s *fileConfigurationStore) Load(siteCode string) ([]byte, error) {
+	content, err := os.ReadFile(s.path(siteCode))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return content, err
+}
+
+// Save writes the configuration to a temporary file and renames it, so a concurrent Load never reads
+// a partially written configuration.
+func (s *fileConfigurationStore) Save(siteCode string, configuration []byte) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, "kameleoon-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(configuration); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(siteCode))
+}
+
+// errConfigurationNotApplied is returned by persist if the fetched configuration isn't the one applied
+// by the Kameleoon client, e.g. if it was published between the poll of the client and the fetch.
+var errConfigurationNotApplied = errors.New("the fetched configuration differs from the applied one")
+
+// configurationPersister fetches the configuration applied by the Kameleoon client and saves it to the store.
+//
+// The Kameleoon client doesn't expose the configuration it applied, so the persister fetches it again and
+// saves it only if its feature flags are the ones of the client. The client notifies configuration updates
+// only in the real-time update mode, so the configuration is also fetched at the refresh interval of the
+// client, which is how often the client polls for it in the default polling mode.
+type configurationPersister struct {
+	siteCode        string
+	store           ConfigurationStore
+	fetch           func() ([]byte, error)
+	applied         func() []string
+	refreshInterval time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// newConfigurationPersister creates a new instance of configurationPersister fetching the configuration
+// from Kameleoon with the given client configuration and checking it against the given client.
+func newConfigurationPersister(
+	siteCode string, store ConfigurationStore, config *kameleoon.KameleoonClientConfig,
+	client kameleoon.KameleoonClient,
+) *configurationPersister {
+	refreshInterval := config.RefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = kameleoon.DefaultRefreshInterval
+	}
+	return &configurationPersister{
+		siteCode: siteCode,
+		store:    store,
+		fetch: func() ([]byte, error) {
+			return fetchRemoteConfiguration(siteCode, config)
+		},
+		applied:         client.GetFeatureList,
+		refreshInterval: refreshInterval,
+		stop:            make(chan struct{}),
+	}
+}
+
+// load returns the stored configuration, ok is false if the store is empty or the configuration is invalid.
+func (cp *configurationPersister) load() (config configuration.Configuration, ok bool) {
+	content, err := cp.store.Load(cp.siteCode)
+	if err != nil || len(content) == 0 {
+		return config, false
+	}
+	return config, parseLocalConfiguration(content, &config) == nil
+}
+
+// persist saves the current remote configuration if the Kameleoon client applied it, an invalid configuration
+// is never saved.
+func (cp *configurationPersister) persist() error {
+	content, err := cp.fetch()
+	if err != nil {
+		return err
+	}
+	var config configuration.Configuration
+	if err = parseLocalConfiguration(content, &config); err != nil {
+		return err
+	}
+	if !sameFeatureFlags(config, cp.applied()) {
+		return errConfigurationNotApplied
+	}
+	return cp.store.Save(cp.siteCode, content)
+}
+
+// update persists the current remote configuration, a failure is logged and the stored one is kept.
+func (cp *configurationPersister) update() {
+	if err := cp.persist(); err != nil {
+		logging.Warning("Failed to persist the Kameleoon configuration: %s", err)
+	}
+}
+
+// run persists the configuration once the live client has loaded it, then at every refresh interval
+// until the persister is closed.
+func (cp *configurationPersister) run(live kameleoon.KameleoonClient, retryInterval time.Duration) {
+	if !waitConfiguration(live, cp.stop, retryInterval, liveMaxRetryInterval) {
+		return
+	}
+	cp.update()
+	ticker := time.NewTicker(cp.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			cp.update()
+		case <-cp.stop:
+			return
+		}
+	}
+}
+
+// close stops persisting the configuration.
+func (cp *configurationPersister) close() {
+	cp.stopOnce.Do(func() { close(cp.stop) })
+}
+
+// sameFeatureFlags reports whether the configuration has exactly the given feature flags.
+func sameFeatureFlags(config configuration.Configuration, featureKeys []string) bool {
+	if len(config.FeatureFlags) != len(featureKeys) {
+		return false
+	}
+	keys := make(map[string]struct{}, len(featureKeys))
+	for _, key := range featureKeys {
+		keys[key] = struct{}{}
+	}
+	for _, featureFlag := range config.FeatureFlags {
+		if _, ok := keys[featureFlag.FeatureKey]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// fetchRemoteConfiguration downloads the configuration served by Kameleoon for the site code. The request is
+// made the way the Kameleoon client makes it from the same config: its environment, network settings
+// (proxy, timeouts and connections) and SDK headers.
+func fetchRemoteConfiguration(siteCode string, config *kameleoon.KameleoonClientConfig) ([]byte, error) {
+	timeout := config.DefaultTimeout
+	if timeout <= 0 {
+		timeout = kameleoon.DefaultRequestTimeout
+	}
+	netProvider := network.NewNetProviderImpl(config.Network.ReadTimeout, config.Network.WriteTimeout,
+		config.Network.MaxConnsPerHost, config.Network.ProxyURL)
+	urlProvider := network.NewUrlProviderImpl(siteCode, network.DefaultDataApiDomain, utils.SdkName, utils.SdkVersion)
+	accessTokenSourceFactory := &network.AccessTokenSourceFactoryImpl{
+		ClientId:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+	}
+	networkManager := network.NewNetworkManagerImpl(config.Environment, timeout, netProvider, urlProvider,
+		accessTokenSourceFactory)
+	content, err := networkManager.FetchConfiguration(-1)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), content...), nil
+}
+
+// staleResolver marks successful resolutions with the CACHED reason while flags are served from
+// a stored configuration.
+type staleResolver struct {
+	resolver resolver
+	stale    func() bool
+}
+
+// newStaleResolver creates a new instance of staleResolver reporting the stale state by the given function.
+func newStaleResolver(r resolver, stale func() bool) *staleResolver {
+	return &staleResolver{resolver: r, stale: stale}
+}
+
+func (r *staleResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *staleResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	res := resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+	if res.err == nil && r.stale() {
+		res.reason = openfeature.CachedReason
+	}
+	return res
+}
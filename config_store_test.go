@@ -0,0 +1,291 @@
+package kameleoon
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryConfigurationStore is a ConfigurationStore signalling every saved configuration.
+type memoryConfigurationStore struct {
+	content []byte
+	saved   chan []byte
+}
+
+func (s *memoryConfigurationStore) Load(siteCode string) ([]byte, error) {
+	return s.content, nil
+}
+
+func (s *memoryConfigurationStore) Save(siteCode string, configuration []byte) error {
+	s.saved <- configuration
+	return nil
+}
+
+func TestFileConfigurationStore_SaveAndLoad(t *testing.T) {
+	// Arrange
+	store := NewFileConfigurationStore(t.TempDir())
+
+	// Act
+	missing, missingErr := store.Load("siteCode")
+	saveErr := store.Save("siteCode", []byte(`{"featureFlags":[]}`))
+	loaded, loadErr := store.Load("siteCode")
+	other, otherErr := store.Load("otherSiteCode")
+
+	// Assert
+	assert.NoError(t, missingErr)
+	assert.Nil(t, missing)
+	assert.NoError(t, saveErr)
+	assert.NoError(t, loadErr)
+	assert.Equal(t, []byte(`{"featureFlags":[]}`), loaded)
+	assert.NoError(t, otherErr)
+	assert.Nil(t, other)
+}
+
+func TestConfigurationPersister_DoesNotSaveInvalidConfiguration(t *testing.T) {
+	// Arrange
+	store := NewFileConfigurationStore(t.TempDir())
+	persister := &configurationPersister{
+		siteCode: "siteCode",
+		store:    store,
+		fetch:    func() ([]byte, error) { return []byte(`{"featureFlags": {`), nil },
+		applied:  func() []string { return nil },
+	}
+	failing := &configurationPersister{
+		siteCode: "siteCode",
+		store:    store,
+		fetch:    func() ([]byte, error) { return nil, errors.New("unavailable") },
+		applied:  func() []string { return nil },
+	}
+
+	// Act
+	invalidErr := persister.persist()
+	fetchErr := failing.persist()
+	stored, _ := store.Load("siteCode")
+
+	// Assert
+	assert.Error(t, invalidErr)
+	assert.Error(t, fetchErr)
+	assert.Nil(t, stored)
+}
+
+func TestKameleoonProvider_ServesStoredConfigurationUntilFreshOneLoads(t *testing.T) {
+	// Arrange
+	visitorCode := "visitor"
+	storedConfig, err := os.ReadFile(testConfigurationPath)
+	require.NoError(t, err)
+	store := &memoryConfigurationStore{content: storedConfig, saved: make(chan []byte, 2)}
+	freshConfig := []byte(`{"featureFlags":[]}`)
+
+	initDone := make(chan time.Time)
+	liveMock := new(MockKameleoonClient)
+	liveMock.On("OnUpdateConfiguration", mock.Anything)
+	liveMock.On("WaitInit").WaitUntil(initDone).Return(nil)
+	liveMock.On("AddData", visitorCode, mock.Anything).Return(nil)
+	liveMock.On("GetFeatureVariationKey", visitorCode, "recommendations", []bool(nil)).Return("treatment", nil)
+	liveMock.On("GetFeatureVariationVariables", "recommendations", "treatment").
+		Return(map[string]interface{}{"count": 10.0}, nil)
+
+	options := newProviderOptions([]Option{WithConfigurationStore(store)})
+	options.persister = &configurationPersister{
+		siteCode:        "siteCode",
+		store:           store,
+		fetch:           func() ([]byte, error) { return freshConfig, nil },
+		applied:         func() []string { return nil },
+		refreshInterval: time.Hour,
+		stop:            make(chan struct{}),
+	}
+	defer options.persister.close()
+	provider, err := newLiveProvider("siteCode", liveMock, "", options)
+	require.NoError(t, err)
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+
+	// Act
+	staleState := provider.Status()
+	staleEvent := <-provider.EventChannel()
+	staleResult := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+	close(initDone)
+	var saved []byte
+	select {
+	case saved = <-store.saved:
+	case <-time.After(time.Second):
+	}
+	var events []openfeature.EventType
+	for len(events) < 2 {
+		events = append(events, (<-provider.EventChannel()).EventType)
+	}
+	freshResult := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+
+	// Assert
+	assert.Equal(t, openfeature.StaleState, staleState)
+	assert.Equal(t, openfeature.ProviderStale, staleEvent.EventType)
+	assert.Equal(t, 5.0, staleResult.Value)
+	assert.Equal(t, openfeature.CachedReason, staleResult.Reason)
+	assert.Equal(t, freshConfig, saved)
+	assert.Equal(t, []openfeature.EventType{openfeature.ProviderConfigChange, openfeature.ProviderReady}, events)
+	assert.Equal(t, openfeature.ReadyState, provider.Status())
+	assert.Equal(t, 10.0, freshResult.Value)
+	assert.NotEqual(t, openfeature.CachedReason, freshResult.Reason)
+}
+
+func TestKameleoonProvider_PersistsConfigurationWhenStoreIsEmpty(t *testing.T) {
+	// Arrange
+	store := &memoryConfigurationStore{saved: make(chan []byte, 1)}
+	freshConfig, err := os.ReadFile(testConfigurationPath)
+	require.NoError(t, err)
+	liveMock := new(MockKameleoonClient)
+	liveMock.On("OnUpdateConfiguration", mock.Anything)
+	liveMock.On("WaitInit").Return(nil)
+	options := newProviderOptions([]Option{WithConfigurationStore(store)})
+	options.persister = &configurationPersister{
+		siteCode:        "siteCode",
+		store:           store,
+		fetch:           func() ([]byte, error) { return freshConfig, nil },
+		applied:         func() []string { return []string{"banner", "recommendations", "layout", "legacy"} },
+		refreshInterval: time.Hour,
+		stop:            make(chan struct{}),
+	}
+	defer options.persister.close()
+
+	// Act
+	provider, err := newLiveProvider("siteCode", liveMock, "", options)
+	var saved []byte
+	select {
+	case saved = <-store.saved:
+	case <-time.After(time.Second):
+	}
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, openfeature.ReadyState, provider.Status())
+	assert.Equal(t, freshConfig, saved)
+}
+
+func TestConfigurationPersister_DoesNotSaveConfigurationNotAppliedByClient(t *testing.T) {
+	// Arrange
+	store := NewFileConfigurationStore(t.TempDir())
+	persister := &configurationPersister{
+		siteCode: "siteCode",
+		store:    store,
+		fetch:    func() ([]byte, error) { return []byte(`{"featureFlags":[{"featureKey":"banner"}]}`), nil },
+		applied:  func() []string { return []string{"banner", "recommendations"} },
+	}
+
+	// Act
+	err := persister.persist()
+	stored, _ := store.Load("siteCode")
+
+	// Assert
+	assert.ErrorIs(t, err, errConfigurationNotApplied)
+	assert.Nil(t, stored)
+}
+
+func TestConfigurationPersister_RefreshesStoredConfigurationAtRefreshInterval(t *testing.T) {
+	// Arrange
+	store := &memoryConfigurationStore{saved: make(chan []byte, 1)}
+	liveMock := new(MockKameleoonClient)
+	liveMock.On("WaitInit").Return(nil)
+	var fetches int
+	persister := &configurationPersister{
+		siteCode: "siteCode",
+		store:    store,
+		fetch: func() ([]byte, error) {
+			fetches++
+			return []byte(fmt.Sprintf(`{"featureFlags":[],"fetch":%d}`, fetches)), nil
+		},
+		applied:         func() []string { return nil },
+		refreshInterval: time.Millisecond,
+		stop:            make(chan struct{}),
+	}
+
+	// Act
+	go persister.run(liveMock, time.Millisecond)
+	first := <-store.saved
+	second := <-store.saved
+	persister.close()
+
+	// Assert
+	assert.Equal(t, `{"featureFlags":[],"fetch":1}`, string(first))
+	assert.Equal(t, `{"featureFlags":[],"fetch":2}`, string(second))
+}
+
+func TestKameleoonProvider_LeavesStoredConfigurationAfterFailedInitialization(t *testing.T) {
+	// Arrange
+	storedConfig, err := os.ReadFile(testConfigurationPath)
+	require.NoError(t, err)
+	store := &memoryConfigurationStore{content: storedConfig, saved: make(chan []byte, 2)}
+	freshConfig := []byte(`{"featureFlags":[{"featureKey":"banner"}]}`)
+	liveMock := new(MockKameleoonClient)
+	liveMock.On("OnUpdateConfiguration", mock.Anything)
+	liveMock.On("WaitInit").Return(context.DeadlineExceeded)
+	liveMock.On("GetFeatureList").Return([]string{}).Twice()
+	liveMock.On("GetFeatureList").Return([]string{"banner"})
+
+	options := newProviderOptions([]Option{WithConfigurationStore(store)})
+	options.liveRetryInterval = time.Millisecond
+	options.persister = &configurationPersister{
+		siteCode:        "siteCode",
+		store:           store,
+		fetch:           func() ([]byte, error) { return freshConfig, nil },
+		applied:         liveMock.GetFeatureList,
+		refreshInterval: time.Hour,
+		stop:            make(chan struct{}),
+	}
+	defer options.persister.close()
+
+	// Act
+	provider, err := newLiveProvider("siteCode", liveMock, "", options)
+	require.NoError(t, err)
+	staleEvent := <-provider.EventChannel()
+	var events []openfeature.EventType
+	for len(events) < 2 {
+		events = append(events, (<-provider.EventChannel()).EventType)
+	}
+	saved := <-store.saved
+
+	// Assert
+	assert.Equal(t, openfeature.ProviderStale, staleEvent.EventType)
+	assert.Equal(t, []openfeature.EventType{openfeature.ProviderConfigChange, openfeature.ProviderReady}, events)
+	assert.Equal(t, openfeature.ReadyState, provider.Status())
+	assert.Equal(t, freshConfig, saved)
+}
+
+func TestFetchRemoteConfiguration_UsesClientNetworkConfig(t *testing.T) {
+	// Arrange
+	proxy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer proxy.Close()
+	requests := make(chan string, 1)
+	go func() {
+		conn, err := proxy.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		requests <- strings.TrimSpace(line)
+		_, _ = conn.Write([]byte("HTTP/1.1 502 Bad Gateway\r\n\r\n"))
+	}()
+	config := &kameleoon.KameleoonClientConfig{
+		DefaultTimeout: 100 * time.Millisecond,
+		Network:        kameleoon.NetworkConfig{ProxyURL: proxy.Addr().String()},
+	}
+
+	// Act
+	_, fetchErr := fetchRemoteConfiguration("siteCode", config)
+
+	// Assert
+	assert.Error(t, fetchErr)
+	assert.Equal(t, "CONNECT sdk-config.kameleoon.eu:443 HTTP/1.1", <-requests)
+}
@@ -0,0 +1,125 @@
+package kameleoon
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// LegalConsentContextKey is the reserved context key with the legal consent of the visitor, a bool or
+// a "true"/"false" string. The consent is set on the Kameleoon client before any data is added.
+const LegalConsentContextKey = "kameleoonLegalConsent"
+
+// untrackedContextKey is the context.Context key marking an evaluation which must not track anything.
+type untrackedContextKey struct{}
+
+// WithLegalConsentAttribute reads the legal consent of the visitor from the context attribute, e.g. "consent",
+// if LegalConsentContextKey isn't set.
+func WithLegalConsentAttribute(attribute string) Option {
+	return func(o *providerOptions) {
+		o.consentAttribute = attribute
+	}
+}
+
+// legalConsent returns the legal consent of the visitor declared in the evaluation context, if any.
+func legalConsent(evalContext openfeature.FlattenedContext, attribute string) (bool, bool) {
+	value, ok := evalContext[LegalConsentContextKey]
+	if !ok && attribute != "" {
+		value, ok = evalContext[attribute]
+	}
+	if !ok {
+		return false, false
+	}
+	switch consent := value.(type) {
+	case bool:
+		return consent, true
+	case string:
+		parsed, err := strconv.ParseBool(consent)
+		return parsed, err == nil
+	}
+	return false, false
+}
+
+// withoutTracking returns a copy of ctx marking the evaluation as untracked and a copy of the evaluation
+// context without Kameleoon data.
+func withoutTracking(
+	ctx context.Context, evalContext openfeature.FlattenedContext,
+) (context.Context, openfeature.FlattenedContext) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	result := make(openfeature.FlattenedContext, len(evalContext))
+	for key, value := range evalContext {
+		if _, ok := dc.conversionMethods[key]; !ok {
+			result[key] = value
+		}
+	}
+	return context.WithValue(ctx, untrackedContextKey{}, true), result
+}
+
+// isUntracked reports whether the evaluation must not track anything.
+func isUntracked(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	untracked, _ := ctx.Value(untrackedContextKey{}).(bool)
+	return untracked
+}
+
+// consentResolver sets the legal consent of the context on the client and evaluates flags for visitors
+// without consent without adding their data or tracking their exposure.
+type consentResolver struct {
+	client    *trackingClient
+	attribute string
+	resolver  resolver
+}
+
+// newConsentResolver creates a new instance of consentResolver.
+func newConsentResolver(client *trackingClient, attribute string, resolver resolver) *consentResolver {
+	return &consentResolver{
+		client:    client,
+		attribute: attribute,
+		resolver:  resolver,
+	}
+}
+
+// Resolve applies the legal consent of the context, then resolves the flag by the wrapped resolver.
+func (r *consentResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *consentResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	ctx, evalContext, err := applyLegalConsent(ctx, r.client, r.attribute, evalContext)
+	if err != nil {
+		return sdkFailure(defaultValue, err, "", nil)
+	}
+	return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+}
+
+// applyLegalConsent sets the legal consent declared in the evaluation context on the client and returns
+// the contexts to evaluate with, untracked and stripped of Kameleoon data if the visitor didn't consent.
+func applyLegalConsent(
+	ctx context.Context, client *trackingClient, attribute string, evalContext openfeature.FlattenedContext,
+) (context.Context, openfeature.FlattenedContext, error) {
+	visitorCode, ok := getTargetingKey(evalContext)
+	if !ok {
+		return ctx, evalContext, nil
+	}
+	consent, ok := legalConsent(evalContext, attribute)
+	if !ok {
+		return ctx, evalContext, nil
+	}
+	if err := client.SetLegalConsent(visitorCode, consent); err != nil {
+		return ctx, evalContext, err
+	}
+	if consent {
+		return ctx, evalContext, nil
+	}
+	ctx, evalContext = withoutTracking(ctx, evalContext)
+	return ctx, evalContext, nil
+}
@@ -0,0 +1,157 @@
+package kameleoon
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestLegalConsent_ReadsReservedKeyThenAttribute(t *testing.T) {
+	tests := []struct {
+		name        string
+		evalContext openfeature.FlattenedContext
+		consent     bool
+		found       bool
+	}{
+		{"bool", openfeature.FlattenedContext{LegalConsentContextKey: true}, true, true},
+		{"string", openfeature.FlattenedContext{LegalConsentContextKey: "false"}, false, true},
+		{"attribute", openfeature.FlattenedContext{"consent": "true"}, true, true},
+		{"reserved key first", openfeature.FlattenedContext{LegalConsentContextKey: false, "consent": true}, false, true},
+		{"invalid", openfeature.FlattenedContext{"consent": "maybe"}, false, false},
+		{"missing", openfeature.FlattenedContext{}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			consent, found := legalConsent(tt.evalContext, "consent")
+
+			// Assert
+			assert.Equal(t, tt.consent, consent)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func TestKameleoonProvider_WithoutConsent_DoesNotAddDataOrTrackExposure(t *testing.T) {
+	// Arrange
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything)
+	clientMock.On("SetLegalConsent", "visitor", false, []*fasthttp.Response(nil)).Return(nil)
+	clientMock.On("AddData", "visitor", []types.Data(nil)).Return(nil)
+	clientMock.On("GetActiveFeatures", "visitor").Return(map[string]types.Variation{
+		"banner": {Key: "on"},
+	}, nil)
+	clientMock.On("GetFeatureVariationVariables", "banner", "off").Return(map[string]interface{}{}, nil)
+	clientMock.On("GetFeatureVariationVariables", "banner", "on").
+		Return(map[string]interface{}{"enabled": true}, nil)
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock)
+
+	// Act
+	result := provider.BooleanEvaluation(context.Background(), "banner", false, openfeature.FlattenedContext{
+		"targetingKey":         "visitor",
+		LegalConsentContextKey: false,
+		Data.Type.Conversion:   map[string]interface{}{Data.ConversionType.GoalId: 1},
+	})
+
+	// Assert
+	assert.True(t, result.Value)
+	assert.Equal(t, "on", result.Variant)
+	assert.True(t, provider.pending.empty())
+	clientMock.AssertNotCalled(t, "GetFeatureVariationKey", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestKameleoonProvider_WithoutConsent_DoesNotFetchRemoteDataOrWarehouseAudiences(t *testing.T) {
+	// Arrange
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything)
+	clientMock.On("SetLegalConsent", "visitor", false, []*fasthttp.Response(nil)).Return(nil)
+	clientMock.On("AddData", "visitor", []types.Data(nil)).Return(nil)
+	clientMock.On("GetActiveFeatures", "visitor").Return(map[string]types.Variation{}, nil)
+	clientMock.On("GetFeatureVariationVariables", "banner", "off").
+		Return(map[string]interface{}{"enabled": false}, nil)
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock,
+		WithRemoteVisitorData(RemoteDataConfig{}), WithWarehouseAudience(WarehouseConfig{CustomDataIndex: 5}))
+
+	// Act
+	result := provider.BooleanEvaluation(context.Background(), "banner", true,
+		openfeature.FlattenedContext{"targetingKey": "visitor", LegalConsentContextKey: false})
+
+	// Assert
+	assert.NoError(t, result.Error())
+	assert.False(t, result.Value)
+	clientMock.AssertNotCalled(t, "GetRemoteVisitorDataWithFilter",
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	clientMock.AssertNotCalled(t, "GetVisitorWarehouseAudience", mock.Anything)
+}
+
+func TestKameleoonProvider_WithConsentAttribute_SetsConsent(t *testing.T) {
+	// Arrange
+	clientMock := newShutdownTestClient()
+	clientMock.On("SetLegalConsent", "visitor", true, []*fasthttp.Response(nil)).Return(nil)
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock, WithLegalConsentAttribute("consent"))
+
+	// Act
+	result := provider.BooleanEvaluation(context.Background(), "banner", false,
+		openfeature.FlattenedContext{"targetingKey": "visitor", "consent": true})
+
+	// Assert
+	assert.True(t, result.Value)
+	clientMock.AssertCalled(t, "SetLegalConsent", "visitor", true, []*fasthttp.Response(nil))
+	clientMock.AssertCalled(t, "GetFeatureVariationKey", "visitor", "banner", []bool(nil))
+}
+
+func TestNewKameleoonProvider_WithoutConsent_SendsNothing(t *testing.T) {
+	if raceEnabled {
+		t.Skip("The Kameleoon SDK loads its configuration without synchronization with WaitInit and the evaluations")
+	}
+
+	// Arrange
+	siteCode := "consentSiteCode"
+	configuration, err := os.ReadFile(testConfigurationPath)
+	require.NoError(t, err)
+	server := kameleoontest.NewServer(siteCode, configuration)
+	defer server.Close()
+
+	provider, err := NewKameleoonProvider(siteCode, server.ClientConfig())
+	require.NoError(t, err)
+	defer provider.Shutdown()
+	require.NoError(t, provider.Init(openfeature.EvaluationContext{}))
+	conversion := map[string]interface{}{Data.ConversionType.GoalId: 1}
+
+	// Act
+	provider.FloatEvaluation(context.Background(), "recommendations", 0, openfeature.FlattenedContext{
+		"targetingKey":         "refusing",
+		LegalConsentContextKey: false,
+		Data.Type.Conversion:   conversion,
+	})
+	provider.FloatEvaluation(context.Background(), "recommendations", 0, openfeature.FlattenedContext{
+		"targetingKey":         "consenting",
+		LegalConsentContextKey: true,
+		Data.Type.Conversion:   conversion,
+	})
+	provider.FlushAll()
+	require.Eventually(t, func() bool { return len(server.TrackedLines()) > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// Assert
+	var refusing, consenting int
+	for _, line := range server.TrackedLines() {
+		if strings.Contains(line, "visitorCode=refusing") {
+			refusing++
+		}
+		if strings.Contains(line, "visitorCode=consenting") {
+			consenting++
+		}
+	}
+	assert.Zero(t, refusing)
+	assert.NotZero(t, consenting)
+}
@@ -1,6 +1,8 @@
 package kameleoon
 
 import (
+	"strings"
+
 	"github.com/Kameleoon/client-go/v3/types"
 	"github.com/open-feature/go-sdk/openfeature"
 )
@@ -16,6 +18,9 @@ func newDataConverter() *dataConverter {
 		conversionMethods: map[string]func(interface{}) types.Data{
 			Data.Type.Conversion: makeConversion,
 			Data.Type.CustomData: makeCustomData,
+			Data.Type.Device:     makeDevice,
+			Data.Type.Browser:    makeBrowser,
+			Data.Type.PageView:   makePageView,
 		},
 	}
 }
@@ -42,7 +47,9 @@ func ToKameleoon(context openfeature.FlattenedContext) []types.Data {
 
 		if conversionMethod, ok := dc.conversionMethods[key]; ok {
 			for _, val := range values {
-				data = append(data, conversionMethod(val))
+				if item := conversionMethod(val); item != nil {
+					data = append(data, item)
+				}
 			}
 		}
 	}
@@ -84,3 +91,54 @@ func makeCustomData(value interface{}) types.Data {
 	}
 	return types.NewCustomData(index, values...)
 }
+
+// makeDevice creates a Device object from the value, one of "desktop", "phone" or "tablet".
+func makeDevice(value interface{}) types.Data {
+	deviceType, ok := value.(string)
+	if !ok {
+		return nil
+	}
+
+	switch device := types.DeviceType(strings.ToUpper(deviceType)); device {
+	case types.DeviceTypeDesktop, types.DeviceTypePhone, types.DeviceTypeTablet:
+		return types.NewDevice(device)
+	}
+	return nil
+}
+
+// makeBrowser creates a Browser object from the value.
+func makeBrowser(value interface{}) types.Data {
+	structData, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	browserName, _ := structData[Data.BrowserType.Type].(string)
+	browserType, ok := types.ParseBrowserType(strings.ToUpper(browserName))
+	if !ok {
+		return nil
+	}
+	switch version := structData[Data.BrowserType.Version].(type) {
+	case float64:
+		return types.NewBrowser(browserType, float32(version))
+	case int:
+		return types.NewBrowser(browserType, float32(version))
+	}
+	return types.NewBrowser(browserType)
+}
+
+// makePageView creates a PageView object from the value.
+func makePageView(value interface{}) types.Data {
+	structData, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	url, ok := structData[Data.PageViewType.Url].(string)
+	if !ok || url == "" {
+		return nil
+	}
+	title, _ := structData[Data.PageViewType.Title].(string)
+	referrers, _ := structData[Data.PageViewType.Referrers].([]int)
+	return types.NewPageViewWithTitle(url, title, referrers...)
+}
@@ -151,3 +151,84 @@ func TestToKameleoonData_AllTypes_ReturnsAllData(t *testing.T) {
 	assert.Equal(t, index1, customData[0].ID())
 	assert.Equal(t, index2, customData[1].ID())
 }
+
+func TestToKameleoon_WithDevice_ReturnsDevice(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected types.DeviceType
+		valid    bool
+	}{
+		{name: "Phone", value: "phone", expected: types.DeviceTypePhone, valid: true},
+		{name: "UpperCase", value: "TABLET", expected: types.DeviceTypeTablet, valid: true},
+		{name: "Unknown", value: "watch"},
+		{name: "NotString", value: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			context := openfeature.FlattenedContext{Data.Type.Device: tt.value}
+
+			// Act
+			result := ToKameleoon(context)
+
+			// Assert
+			if !tt.valid {
+				assert.Empty(t, result)
+				return
+			}
+			assert.Len(t, result, 1)
+			device, ok := result[0].(*types.Device)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, device.Type())
+		})
+	}
+}
+
+func TestToKameleoon_WithBrowser_ReturnsBrowser(t *testing.T) {
+	// Arrange
+	context := openfeature.FlattenedContext{
+		Data.Type.Browser: map[string]interface{}{
+			Data.BrowserType.Type:    "firefox",
+			Data.BrowserType.Version: 120,
+		},
+	}
+
+	// Act
+	result := ToKameleoon(context)
+
+	// Assert
+	assert.Len(t, result, 1)
+	browser, ok := result[0].(*types.Browser)
+	assert.True(t, ok)
+	assert.Equal(t, types.BrowserTypeFirefox, browser.Type())
+	assert.Equal(t, float32(120), browser.Version())
+}
+
+func TestToKameleoon_WithPageView_ReturnsPageView(t *testing.T) {
+	// Arrange
+	context := openfeature.FlattenedContext{
+		Data.Type.PageView: []map[string]interface{}{
+			{
+				Data.PageViewType.Url:       "https://example.com/offers",
+				Data.PageViewType.Title:     "Offers",
+				Data.PageViewType.Referrers: []int{3},
+			},
+			{
+				Data.PageViewType.Title: "Without url",
+			},
+		},
+	}
+
+	// Act
+	result := ToKameleoon(context)
+
+	// Assert
+	assert.Len(t, result, 1)
+	pageView, ok := result[0].(*types.PageView)
+	assert.True(t, ok)
+	assert.Equal(t, "https://example.com/offers", pageView.URL())
+	assert.Equal(t, "Offers", pageView.Title())
+	assert.Equal(t, []int{3}, pageView.Referrers())
+}
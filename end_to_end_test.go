@@ -0,0 +1,55 @@
+//go:build !race
+
+// The Kameleoon SDK has a data race in WaitInit, between the goroutine loading the configuration and
+// the waiting one, so the tests running a real Kameleoon client are excluded from race builds.
+
+package kameleoon
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKameleoonProvider_EndToEndWithFakeServer(t *testing.T) {
+	// Arrange
+	siteCode := "endToEndSiteCode"
+	configuration, err := os.ReadFile(testConfigurationPath)
+	require.NoError(t, err)
+	server := kameleoontest.NewServer(siteCode, configuration)
+	defer server.Close()
+	config := server.ClientConfig()
+	config.TrackingInterval = 100 * time.Millisecond
+
+	provider, err := NewKameleoonProvider(siteCode, config)
+	require.NoError(t, err)
+	defer provider.Shutdown()
+	evalContext := openfeature.FlattenedContext{
+		"targetingKey": "visitor",
+		Data.Type.CustomData: map[string]interface{}{
+			Data.CustomDataType.Index:  1,
+			Data.CustomDataType.Values: "premium",
+		},
+	}
+
+	// Act
+	initErr := provider.Init(openfeature.EvaluationContext{})
+	title := provider.StringEvaluation(context.Background(), "banner", "", evalContext)
+	recommendations := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+	provider.GetClient().FlushAll(true)
+	require.Eventually(t, func() bool { return len(server.TrackedLines()) > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// Assert
+	assert.NoError(t, initErr)
+	assert.Equal(t, openfeature.ReadyState, provider.Status())
+	assert.Equal(t, "Premium offer", title.Value)
+	assert.Equal(t, 5.0, recommendations.Value)
+	assert.Equal(t, 1, server.Requests(kameleoontest.EndpointConfiguration))
+	assert.Contains(t, server.TrackedLines()[0], "visitorCode=visitor")
+}
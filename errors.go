@@ -0,0 +1,125 @@
+package kameleoon
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Kameleoon/client-go/v3/errs"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	// ErrorMetadataKey is the flag metadata key with the message of the Kameleoon SDK error of an evaluation
+	// which failed because of the Kameleoon SDK.
+	ErrorMetadataKey = "kameleoonError"
+	// ErrorCodeMetadataKey is the flag metadata key with the OpenFeature error code the Kameleoon SDK error
+	// was translated to.
+	ErrorCodeMetadataKey = "kameleoonErrorCode"
+)
+
+// EvaluationError is a Kameleoon SDK error translated to an OpenFeature error code. It wraps the SDK error,
+// so errors.Is and errors.As find it, e.g. *errs.VisitorCodeInvalid.
+type EvaluationError struct {
+	// Code is the OpenFeature error code of the evaluation.
+	Code openfeature.ErrorCode
+	// Reason is the OpenFeature reason of the evaluation.
+	Reason openfeature.Reason
+	// Err is the error returned by the Kameleoon SDK.
+	Err error
+}
+
+// Error returns the error code and the message of the SDK error.
+func (e *EvaluationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Err)
+}
+
+// Unwrap returns the error returned by the Kameleoon SDK.
+func (e *EvaluationError) Unwrap() error {
+	return e.Err
+}
+
+// ResolutionError returns the OpenFeature resolution error with the code of the evaluation error.
+func (e *EvaluationError) ResolutionError() openfeature.ResolutionError {
+	msg := e.Err.Error()
+	switch e.Code {
+	case openfeature.ProviderNotReadyCode:
+		return openfeature.NewProviderNotReadyResolutionError(msg)
+	case openfeature.InvalidContextCode:
+		return openfeature.NewInvalidContextResolutionError(msg)
+	case openfeature.FlagNotFoundCode:
+		return openfeature.NewFlagNotFoundResolutionError(msg)
+	}
+	return openfeature.NewGeneralResolutionError(msg)
+}
+
+// TranslateError translates an error returned by the Kameleoon SDK to an OpenFeature error code and reason:
+//   - invalid visitor codes are INVALID_CONTEXT,
+//   - unknown feature flags, variations and variables are FLAG_NOT_FOUND,
+//   - feature flags disabled in the environment have the DISABLED reason, the provider returns the default
+//     value without error for them,
+//   - invalid credentials, an empty site code, configuration errors and ErrLiveClientNotReady
+//     are PROVIDER_NOT_READY,
+//   - other errors, e.g. network errors, are GENERAL.
+func TranslateError(err error) *EvaluationError {
+	var evalErr *EvaluationError
+	if errors.As(err, &evalErr) {
+		return evalErr
+	}
+	var (
+		visitorCodeInvalid         *errs.VisitorCodeInvalid
+		featureNotFound            *errs.FeatureNotFound
+		featureVariationNotFound   *errs.FeatureVariationNotFound
+		featureVariableNotFound    *errs.FeatureVariableNotFound
+		featureEnvironmentDisabled *errs.FeatureEnvironmentDisabled
+		configCredentialsInvalid   *errs.ConfigCredentialsInvalid
+		siteCodeIsEmpty            *errs.SiteCodeIsEmpty
+		configError                *errs.ConfigError
+	)
+	code, reason := openfeature.GeneralCode, openfeature.ErrorReason
+	switch {
+	case errors.As(err, &visitorCodeInvalid):
+		code = openfeature.InvalidContextCode
+	case errors.As(err, &featureNotFound), errors.As(err, &featureVariationNotFound),
+		errors.As(err, &featureVariableNotFound):
+		code = openfeature.FlagNotFoundCode
+	case errors.As(err, &featureEnvironmentDisabled):
+		reason = openfeature.DisabledReason
+	case errors.As(err, &configCredentialsInvalid), errors.As(err, &siteCodeIsEmpty), errors.As(err, &configError),
+		errors.Is(err, ErrLiveClientNotReady):
+		code = openfeature.ProviderNotReadyCode
+	}
+	return &EvaluationError{Code: code, Reason: reason, Err: err}
+}
+
+// sdkFailure returns the resolution of an evaluation which failed because of the Kameleoon SDK error.
+// Feature flags disabled in the environment aren't failures: the default value is returned for the "off"
+// variation with the DISABLED reason.
+func sdkFailure(
+	defaultValue interface{}, err error, variant string, metadata openfeature.FlagMetadata,
+) resolution {
+	evalErr := TranslateError(err)
+	if evalErr.Reason == openfeature.DisabledReason {
+		return resolution{
+			value:    defaultValue,
+			variant:  string(types.VariationOff),
+			reason:   openfeature.DisabledReason,
+			metadata: metadata,
+		}
+	}
+	resError := evalErr.ResolutionError()
+	result := errorMetadata(evalErr, ErrorMetadataKey, ErrorCodeMetadataKey)
+	for key, value := range metadata {
+		result[key] = value
+	}
+	return resolution{value: defaultValue, err: &resError, variant: variant, reason: evalErr.Reason, metadata: result}
+}
+
+// errorMetadata returns the flag metadata with the message and the code of the evaluation error under the given
+// keys. Flag metadata only holds bool, string and number values, so the error itself isn't stored.
+func errorMetadata(evalErr *EvaluationError, messageKey, codeKey string) openfeature.FlagMetadata {
+	return openfeature.FlagMetadata{
+		messageKey: evalErr.Err.Error(),
+		codeKey:    string(evalErr.Code),
+	}
+}
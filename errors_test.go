@@ -0,0 +1,88 @@
+package kameleoon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Kameleoon/client-go/v3/errs"
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateError_MapsSDKErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   openfeature.ErrorCode
+		reason openfeature.Reason
+	}{
+		{"visitor code invalid", errs.NewVisitorCodeInvalid("is empty"), openfeature.InvalidContextCode,
+			openfeature.ErrorReason},
+		{"feature not found", errs.NewFeatureNotFound("flag"), openfeature.FlagNotFoundCode, openfeature.ErrorReason},
+		{"variation not found", errs.NewFeatureVariationNotFound("flag", "on"), openfeature.FlagNotFoundCode,
+			openfeature.ErrorReason},
+		{"variable not found", errs.NewFeatureVariableNotFound("flag", "on", "title"), openfeature.FlagNotFoundCode,
+			openfeature.ErrorReason},
+		{"environment disabled", errs.NewFeatureEnvironmentDisabled("flag", "production"), openfeature.GeneralCode,
+			openfeature.DisabledReason},
+		{"credentials invalid", errs.NewConfigCredentialsInvalid("client"), openfeature.ProviderNotReadyCode,
+			openfeature.ErrorReason},
+		{"site code empty", errs.NewSiteCodeIsEmpty("empty"), openfeature.ProviderNotReadyCode,
+			openfeature.ErrorReason},
+		{"live client not ready", ErrLiveClientNotReady, openfeature.ProviderNotReadyCode, openfeature.ErrorReason},
+		{"unexpected status code", errs.NewUnexpectedStatusCode(503), openfeature.GeneralCode,
+			openfeature.ErrorReason},
+		{"internal error", errs.NewInternalError("panic"), openfeature.GeneralCode, openfeature.ErrorReason},
+		{"network error", errors.New("connection refused"), openfeature.GeneralCode, openfeature.ErrorReason},
+		{"wrapped", fmt.Errorf("wrapped: %w", errs.NewFeatureNotFound("flag")), openfeature.FlagNotFoundCode,
+			openfeature.ErrorReason},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			evalErr := TranslateError(tt.err)
+
+			// Assert
+			assert.Equal(t, tt.code, evalErr.Code)
+			assert.Equal(t, tt.reason, evalErr.Reason)
+			assert.ErrorIs(t, evalErr, tt.err)
+			assert.Equal(t, tt.code, openfeature.ProviderResolutionDetail{
+				ResolutionError: evalErr.ResolutionError(),
+			}.ResolutionDetail().ErrorCode)
+		})
+	}
+}
+
+func TestTranslateError_KeepsEvaluationError(t *testing.T) {
+	// Arrange
+	evalErr := TranslateError(errs.NewFeatureNotFound("flag"))
+
+	// Act
+	translated := TranslateError(fmt.Errorf("snapshot: %w", evalErr))
+
+	// Assert
+	assert.Same(t, evalErr, translated)
+}
+
+func TestKameleoonProvider_SDKError_ReportsErrorInMetadata(t *testing.T) {
+	// Arrange
+	provider := NewKameleoonProviderWithClient("siteCode", kameleoontest.NewClient())
+	defer provider.Shutdown()
+
+	// Act
+	result := provider.BooleanEvaluation(context.Background(), "unknown", false,
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+	// Assert
+	assert.Equal(t, openfeature.FlagNotFoundCode, result.ResolutionDetail().ErrorCode)
+	message, err := result.FlagMetadata.GetString(ErrorMetadataKey)
+	require.NoError(t, err)
+	assert.Contains(t, message, "unknown")
+	code, err := result.FlagMetadata.GetString(ErrorCodeMetadataKey)
+	require.NoError(t, err)
+	assert.Equal(t, string(openfeature.FlagNotFoundCode), code)
+}
@@ -0,0 +1,122 @@
+package kameleoon
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// FlushPolicy defines when the provider sends the pending visitor data to Kameleoon, on top of the tracking
+// interval of the Kameleoon client. Rules can be combined, the zero value adds none.
+type FlushPolicy struct {
+	// OnConversion flushes the visitor instantly after an evaluation whose context has a conversion.
+	OnConversion bool
+	// EveryEvaluations flushes all pending data after every N evaluations, zero disables the rule.
+	EveryEvaluations int
+	// Interval flushes all pending data periodically, zero disables the rule.
+	Interval time.Duration
+}
+
+// WithFlushPolicy makes the provider flush the visitor data according to the policy, so the data reaches
+// Kameleoon promptly in short-lived processes like serverless functions and CLI jobs.
+func WithFlushPolicy(policy FlushPolicy) Option {
+	return func(o *providerOptions) {
+		o.flushPolicy = policy
+	}
+}
+
+// Flush instantly sends the pending data of the visitor to Kameleoon.
+func (p *kameleoonProvider) Flush(visitorCode string) error {
+	if p.isShutDown() {
+		return openfeature.NewProviderNotReadyResolutionError("The Kameleoon provider is shut down")
+	}
+	return p.tracked.FlushVisitorInstantly(visitorCode)
+}
+
+// FlushAll instantly sends the pending data of all visitors to Kameleoon.
+func (p *kameleoonProvider) FlushAll() {
+	if p.isShutDown() {
+		return
+	}
+	p.tracked.FlushAll(true)
+}
+
+// intervalFlusher flushes the pending visitor data periodically until it's stopped.
+type intervalFlusher struct {
+	client   *trackingClient
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// newIntervalFlusher creates a new instance of intervalFlusher and starts flushing.
+func newIntervalFlusher(client *trackingClient, interval time.Duration) *intervalFlusher {
+	f := &intervalFlusher{
+		client: client,
+		stop:   make(chan struct{}),
+	}
+	go f.run(interval)
+	return f
+}
+
+func (f *intervalFlusher) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !f.client.pending.empty() {
+				f.client.FlushAll(true)
+			}
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+// close stops flushing.
+func (f *intervalFlusher) close() {
+	f.stopOnce.Do(func() { close(f.stop) })
+}
+
+// flushResolver flushes the visitor data after evaluations according to the flush policy.
+type flushResolver struct {
+	client      *trackingClient
+	policy      FlushPolicy
+	resolver    resolver
+	evaluations int64
+}
+
+// newFlushResolver creates a new instance of flushResolver.
+func newFlushResolver(client *trackingClient, policy FlushPolicy, resolver resolver) *flushResolver {
+	return &flushResolver{
+		client:   client,
+		policy:   policy,
+		resolver: resolver,
+	}
+}
+
+// Resolve resolves the flag by the wrapped resolver, then flushes the data if the policy requires it.
+func (r *flushResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *flushResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	res := resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+	if n := int64(r.policy.EveryEvaluations); n > 0 && atomic.AddInt64(&r.evaluations, 1)%n == 0 {
+		r.client.FlushAll(true)
+		return res
+	}
+	if _, ok := evalContext[Data.Type.Conversion]; ok && r.policy.OnConversion {
+		if visitorCode, ok := getTargetingKey(evalContext); ok {
+			_ = r.client.FlushVisitorInstantly(visitorCode)
+		}
+	}
+	return res
+}
@@ -0,0 +1,112 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestKameleoonProvider_Flush_SendsVisitorData(t *testing.T) {
+	// Arrange
+	clientMock := newShutdownTestClient()
+	clientMock.On("FlushVisitorInstantly", "visitor").Return(nil)
+	clientMock.On("FlushAll", []bool{true})
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock)
+	provider.BooleanEvaluation(context.Background(), "banner", false,
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+	// Act
+	err := provider.Flush("visitor")
+	pendingAfterFlush := provider.pending.empty()
+	provider.FlushAll()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, pendingAfterFlush)
+	clientMock.AssertCalled(t, "FlushVisitorInstantly", "visitor")
+	clientMock.AssertCalled(t, "FlushAll", []bool{true})
+}
+
+func TestKameleoonProvider_Flush_AfterShutdown_ReturnsError(t *testing.T) {
+	// Arrange
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything)
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock)
+	provider.Shutdown()
+
+	// Act
+	err := provider.Flush("visitor")
+	provider.FlushAll()
+
+	// Assert
+	assert.Error(t, err)
+	clientMock.AssertNotCalled(t, "FlushVisitorInstantly", mock.Anything)
+	clientMock.AssertNotCalled(t, "FlushAll", mock.Anything)
+}
+
+func TestFlushPolicy_OnConversion_FlushesVisitor(t *testing.T) {
+	// Arrange
+	clientMock := newShutdownTestClient()
+	clientMock.On("FlushVisitorInstantly", "visitor").Return(nil)
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock,
+		WithFlushPolicy(FlushPolicy{OnConversion: true}))
+
+	// Act
+	provider.BooleanEvaluation(context.Background(), "banner", false,
+		openfeature.FlattenedContext{"targetingKey": "other"})
+	provider.BooleanEvaluation(context.Background(), "banner", false, openfeature.FlattenedContext{
+		"targetingKey":       "visitor",
+		Data.Type.Conversion: map[string]interface{}{Data.ConversionType.GoalId: 1},
+	})
+
+	// Assert
+	clientMock.AssertNumberOfCalls(t, "FlushVisitorInstantly", 1)
+	clientMock.AssertCalled(t, "FlushVisitorInstantly", "visitor")
+}
+
+func TestFlushPolicy_EveryEvaluations_FlushesAll(t *testing.T) {
+	// Arrange
+	clientMock := newShutdownTestClient()
+	clientMock.On("FlushAll", []bool{true})
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock,
+		WithFlushPolicy(FlushPolicy{EveryEvaluations: 2}))
+	evalContext := openfeature.FlattenedContext{"targetingKey": "visitor"}
+
+	// Act
+	for i := 0; i < 5; i++ {
+		provider.BooleanEvaluation(context.Background(), "banner", false, evalContext)
+	}
+
+	// Assert
+	clientMock.AssertNumberOfCalls(t, "FlushAll", 2)
+}
+
+func TestFlushPolicy_Interval_FlushesPendingData(t *testing.T) {
+	// Arrange
+	flushed := make(chan struct{}, 1)
+	clientMock := newShutdownTestClient()
+	clientMock.On("FlushAll", []bool{true}).Run(func(mock.Arguments) {
+		select {
+		case flushed <- struct{}{}:
+		default:
+		}
+	})
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock,
+		WithFlushPolicy(FlushPolicy{Interval: 10 * time.Millisecond}))
+	defer provider.Shutdown()
+
+	// Act
+	provider.BooleanEvaluation(context.Background(), "banner", false,
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+	// Assert
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+		assert.Fail(t, "pending data wasn't flushed")
+	}
+}
@@ -7,6 +7,7 @@ require (
 	github.com/open-feature/go-sdk v1.12.0
 	github.com/stretchr/testify v1.8.4
 	github.com/valyala/fasthttp v1.34.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -31,5 +32,4 @@ require (
 	golang.org/x/sys v0.5.0 // indirect
 	golang.org/x/text v0.15.0 // indirect
 	gopkg.in/yaml.v2 v2.3.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,99 @@
+package kameleoon
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// UserIDContextKey is the reserved context key with the identifier of a logged-in visitor, e.g. a user ID,
+// whose targeting key stays the anonymous visitor code. It's only used with WithIdentityMerge.
+const UserIDContextKey = "kameleoonUserId"
+
+// WithIdentityMerge links anonymous visitors to their identifier once they log in. The identifier set with
+// UserIDContextKey is added as the custom data at mappingIndex, which must be defined as the mapping identifier
+// in Kameleoon. Kameleoon then links the visits of the anonymous visitor code to the identifier. The Kameleoon
+// client keeps assigning the variations by the anonymous visitor code, which it sets as the mapping identifier
+// of the visitor, so a visitor logging in on another device, with another anonymous visitor code, may get
+// other variations.
+func WithIdentityMerge(mappingIndex int) Option {
+	return func(o *providerOptions) {
+		o.identityMappingIndex = &mappingIndex
+	}
+}
+
+// userID returns the identifier of the logged-in visitor declared in the evaluation context, if any.
+func userID(evalContext openfeature.FlattenedContext) (string, bool) {
+	id, ok := evalContext[UserIDContextKey].(string)
+	return id, ok && id != ""
+}
+
+// applyIdentityMerge returns the evaluation context with the identifier of the logged-in visitor added as
+// the mapping identifier custom data of the anonymous visitor. The context is unchanged if identities aren't
+// merged, if it lacks the targeting key or the identifier, or if the evaluation is untracked.
+func applyIdentityMerge(
+	ctx context.Context, mappingIndex *int, evalContext openfeature.FlattenedContext,
+) openfeature.FlattenedContext {
+	if mappingIndex == nil || isUntracked(ctx) {
+		return evalContext
+	}
+	if _, ok := getTargetingKey(evalContext); !ok {
+		return evalContext
+	}
+	id, ok := userID(evalContext)
+	if !ok {
+		return evalContext
+	}
+	return withCustomData(evalContext, map[string]interface{}{
+		Data.CustomDataType.Index:  *mappingIndex,
+		Data.CustomDataType.Values: id,
+	})
+}
+
+// withCustomData returns the evaluation context with the custom data added to its custom data.
+func withCustomData(
+	evalContext openfeature.FlattenedContext, data map[string]interface{},
+) openfeature.FlattenedContext {
+	var customData []map[string]interface{}
+	switch value := evalContext[Data.Type.CustomData].(type) {
+	case map[string]interface{}:
+		customData = []map[string]interface{}{value, data}
+	case []map[string]interface{}:
+		customData = append(append(make([]map[string]interface{}, 0, len(value)+1), value...), data)
+	default:
+		customData = []map[string]interface{}{data}
+	}
+	result := make(openfeature.FlattenedContext, len(evalContext))
+	for key, value := range evalContext {
+		result[key] = value
+	}
+	result[Data.Type.CustomData] = customData
+	return result
+}
+
+// identityResolver merges the identity of logged-in visitors into their anonymous visitor.
+type identityResolver struct {
+	mappingIndex *int
+	resolver     resolver
+}
+
+// newIdentityResolver creates a new instance of identityResolver.
+func newIdentityResolver(mappingIndex *int, resolver resolver) *identityResolver {
+	return &identityResolver{
+		mappingIndex: mappingIndex,
+		resolver:     resolver,
+	}
+}
+
+// Resolve resolves the flag by the wrapped resolver with the identity of the logged-in visitor merged.
+func (r *identityResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *identityResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	return resolveDetail(r.resolver, ctx, flag, defaultValue, applyIdentityMerge(ctx, r.mappingIndex, evalContext))
+}
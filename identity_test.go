@@ -0,0 +1,127 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyIdentityMerge_AddsMappingIdentifier(t *testing.T) {
+	mappingIndex := 7
+	mapping := map[string]interface{}{Data.CustomDataType.Index: 7, Data.CustomDataType.Values: "user"}
+	other := map[string]interface{}{Data.CustomDataType.Index: 1, Data.CustomDataType.Values: "premium"}
+	tests := []struct {
+		name         string
+		mappingIndex *int
+		untracked    bool
+		evalContext  openfeature.FlattenedContext
+		customData   interface{}
+	}{
+		{
+			name:         "disabled",
+			evalContext:  openfeature.FlattenedContext{"targetingKey": "anonymous", UserIDContextKey: "user"},
+			customData:   nil,
+			mappingIndex: nil,
+		},
+		{
+			name:         "anonymous",
+			mappingIndex: &mappingIndex,
+			evalContext:  openfeature.FlattenedContext{"targetingKey": "anonymous"},
+			customData:   nil,
+		},
+		{
+			name:         "untracked",
+			mappingIndex: &mappingIndex,
+			untracked:    true,
+			evalContext:  openfeature.FlattenedContext{"targetingKey": "anonymous", UserIDContextKey: "user"},
+			customData:   nil,
+		},
+		{
+			name:         "without targeting key",
+			mappingIndex: &mappingIndex,
+			evalContext:  openfeature.FlattenedContext{UserIDContextKey: "user"},
+			customData:   nil,
+		},
+		{
+			name:         "logged in",
+			mappingIndex: &mappingIndex,
+			evalContext:  openfeature.FlattenedContext{"targetingKey": "anonymous", UserIDContextKey: "user"},
+			customData:   []map[string]interface{}{mapping},
+		},
+		{
+			name:         "with custom data",
+			mappingIndex: &mappingIndex,
+			evalContext: openfeature.FlattenedContext{
+				"targetingKey": "anonymous", UserIDContextKey: "user", Data.Type.CustomData: other,
+			},
+			customData: []map[string]interface{}{other, mapping},
+		},
+		{
+			name:         "with custom data list",
+			mappingIndex: &mappingIndex,
+			evalContext: openfeature.FlattenedContext{
+				"targetingKey": "anonymous", UserIDContextKey: "user",
+				Data.Type.CustomData: []map[string]interface{}{other},
+			},
+			customData: []map[string]interface{}{other, mapping},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			if tt.untracked {
+				ctx, _ = withoutTracking(ctx, nil)
+			}
+
+			// Act
+			result := applyIdentityMerge(ctx, tt.mappingIndex, tt.evalContext)
+
+			// Assert
+			assert.Equal(t, tt.customData, result[Data.Type.CustomData])
+			assert.Equal(t, tt.evalContext["targetingKey"], result["targetingKey"])
+		})
+	}
+}
+
+func TestKameleoonProvider_WithIdentityMerge_LinksAnonymousVisitor(t *testing.T) {
+	// Arrange
+	client := newTransactionTestClient()
+	provider := NewKameleoonProviderWithClient("siteCode", client, WithIdentityMerge(7))
+	defer provider.Shutdown()
+
+	// Act
+	result := provider.BooleanEvaluation(context.Background(), "banner", false, openfeature.FlattenedContext{
+		"targetingKey":   "anonymous",
+		UserIDContextKey: "user",
+	})
+
+	// Assert
+	assert.True(t, result.Value)
+	data := client.VisitorData("anonymous")
+	assert.Len(t, data, 1)
+	customData := data[0].(*types.CustomData)
+	assert.Equal(t, 7, customData.ID())
+	assert.Equal(t, []string{"user"}, customData.Values())
+	assert.Empty(t, client.VisitorData("user"))
+}
+
+func TestKameleoonProvider_WithIdentityMerge_WithoutConsent_DoesNotLink(t *testing.T) {
+	// Arrange
+	client := newTransactionTestClient()
+	provider := NewKameleoonProviderWithClient("siteCode", client, WithIdentityMerge(7))
+	defer provider.Shutdown()
+
+	// Act
+	provider.BooleanEvaluation(context.Background(), "banner", false, openfeature.FlattenedContext{
+		"targetingKey":         "anonymous",
+		UserIDContextKey:       "user",
+		LegalConsentContextKey: false,
+	})
+
+	// Assert
+	assert.Empty(t, client.VisitorData("anonymous"))
+}
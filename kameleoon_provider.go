@@ -2,29 +2,190 @@ package kameleoon
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	kameleoon "github.com/Kameleoon/client-go/v3"
 	"github.com/open-feature/go-sdk/openfeature"
 )
 
 const META_NAME = "Kameleoon Provider"
 
+// eventBufferSize is the capacity of the provider event channel.
+const eventBufferSize = 16
+
+// providerIDs generates the ids of providers.
+var providerIDs uint64
+
 type kameleoonProvider struct {
+	// id is unique per provider. The OpenFeature SDK compares providers with reflect.DeepEqual, the id being
+	// the first field the comparison of two providers stops before reading the state changed by Shutdown.
+	id       uint64
 	siteCode string
 	client   kameleoon.KameleoonClient
 	resolver resolver
+	cache    *evaluationCache
+	events   chan openfeature.Event
+	// shared is set if the client is created by KameleoonClientFactory and shared with other providers.
+	shared       *sharedClient
+	persister    *configurationPersister
+	readiness    *clientReadiness
+	overrides    *overrideRegistry
+	overrideFile *overrideFile
+	ready        int32
+	// tracked is the client used for evaluations, recording the visitors with pending data.
+	tracked         *trackingClient
+	pending         *pendingVisitors
+	shutdownTimeout time.Duration
+	flusher         *intervalFlusher
+	shutdown        int32
+	shutdownOnce    sync.Once
+	// consentAttribute is the context attribute with the legal consent, besides LegalConsentContextKey.
+	consentAttribute string
+	// identityMappingIndex is the mapping identifier custom data index if identities are merged, nil otherwise.
+	identityMappingIndex *int
+	// strictBoolean makes boolean evaluations require a bool variable.
+	strictBoolean bool
+	// variationKeyFlags are the feature flags whose string evaluations return the variation key.
+	variationKeyFlags map[string]struct{}
+	// targetingKeys finds and normalizes the visitor code of evaluation contexts.
+	targetingKeys *targetingKeyPolicy
+	// remoteData fetches the remote visitor data before evaluations if it's enabled, nil otherwise.
+	remoteData *remoteDataLoader
+	// warehouse fetches the warehouse audiences before evaluations if it's enabled, nil otherwise.
+	warehouse *warehouseAudiences
 }
 
 // NewKameleoonProvider creates a new instance of kameleoonProvider with the given siteCode and client configuration.
-func NewKameleoonProvider(siteCode string, config *kameleoon.KameleoonClientConfig) (*kameleoonProvider, error) {
-	client, err := kameleoon.KameleoonClientFactory.Create(siteCode, config)
+// Optional behaviour of the provider can be enabled with the given options. Providers created for the same siteCode
+// share one client, which is released by KameleoonClientFactory.Forget when the last of them shuts down.
+func NewKameleoonProvider(
+	siteCode string, config *kameleoon.KameleoonClientConfig, opts ...Option,
+) (*kameleoonProvider, error) {
+	options := newProviderOptions(opts)
+	client, err := factoryClients.acquire(siteCode, config)
 	if err != nil {
 		return nil, openfeature.NewProviderNotReadyResolutionError(err.Error())
 	}
-	return &kameleoonProvider{
-		siteCode: siteCode,
-		client:   client,
-		resolver: newKameleoonResolver(client),
-	}, nil
+	if options.configStore != nil {
+		options.persister = newConfigurationPersister(siteCode, options.configStore, config, client)
+	}
+	p, err := newLiveProvider(siteCode, client, config.Environment, options)
+	if err != nil {
+		client.release()
+		return nil, err
+	}
+	p.shared = client
+	return p, nil
+}
+
+// NewKameleoonProviderWithClient creates a new instance of kameleoonProvider for the given client, e.g. a fake
+// client of the kameleoontest package. The client isn't released by Shutdown.
+func NewKameleoonProviderWithClient(
+	siteCode string, client kameleoon.KameleoonClient, opts ...Option,
+) *kameleoonProvider {
+	return newProvider(siteCode, client, newProviderOptions(opts))
+}
+
+// newLiveProvider creates a new instance of kameleoonProvider for the live client. Until the client is
+// initialized, flags are served from the stored configuration or from the bootstrap one if any.
+func newLiveProvider(
+	siteCode string, live kameleoon.KameleoonClient, environment string, options *providerOptions,
+) (*kameleoonProvider, error) {
+	var client kameleoon.KameleoonClient = live
+	if options.persister != nil {
+		if storedConfig, ok := options.persister.load(); ok {
+			client = newBootstrapClient(newLocalClient(storedConfig, environment), live, true, options)
+		}
+	}
+	if client == live && options.bootstrapPath != "" {
+		localConfig, err := loadLocalConfiguration(options.bootstrapPath)
+		if err != nil {
+			return nil, openfeature.NewProviderNotReadyResolutionError(err.Error())
+		}
+		client = newBootstrapClient(newLocalClient(localConfig, environment), live, false, options)
+	}
+	if client == live {
+		options.readiness = newClientReadiness(live, false)
+	}
+	p := newProvider(siteCode, client, options)
+	if p.isStale() {
+		p.emit(openfeature.ProviderStale, "Kameleoon provider serves the stored configuration")
+	}
+	if options.persister != nil {
+		go options.persister.run(live, options.liveRetryInterval)
+	}
+	return p, nil
+}
+
+// newProvider creates a new instance of kameleoonProvider for the given client and builds its resolver chain.
+// The client is considered ready unless the options hold the readiness of a live client.
+func newProvider(siteCode string, client kameleoon.KameleoonClient, options *providerOptions) *kameleoonProvider {
+	readiness := options.readiness
+	if readiness == nil {
+		readiness = newClientReadiness(client, true)
+	}
+	p := &kameleoonProvider{
+		id:                   atomic.AddUint64(&providerIDs, 1),
+		siteCode:             siteCode,
+		client:               client,
+		events:               make(chan openfeature.Event, eventBufferSize),
+		persister:            options.persister,
+		readiness:            readiness,
+		overrides:            newOverrideRegistry(),
+		pending:              newPendingVisitors(),
+		shutdownTimeout:      options.shutdownTimeout,
+		consentAttribute:     options.consentAttribute,
+		identityMappingIndex: options.identityMappingIndex,
+		strictBoolean:        options.strictBoolean,
+		variationKeyFlags:    options.variationKeyFlags,
+		targetingKeys: newTargetingKeyPolicy(
+			options.targetingKeyFallback, options.anonymousVisitors, options.visitorCodeNormalization),
+	}
+	p.tracked = newTrackingClient(client, p.pending)
+	var r resolver = newKameleoonResolver(p.tracked)
+	if options.persister != nil {
+		r = newStaleResolver(r, p.isStale)
+	}
+	if options.cacheConfig != nil {
+		p.cache = newEvaluationCache(*options.cacheConfig)
+		r = newCachingResolver(r, p.cache)
+	}
+	r = newSnapshotResolver(p.tracked, r)
+	r = newReadinessResolver(r, p.readiness.isReady)
+	if options.overrideFilePath != "" {
+		p.overrideFile = newOverrideFile(
+			options.overrideFilePath, options.overrideFilePollInterval, p.onOverrideFileChange)
+		r = newFileOverrideResolver(p.tracked, p.overrideFile, r)
+	}
+	r = newOverrideResolver(p.tracked, p.overrides, options.contextOverrides, r)
+	if options.flushPolicy.OnConversion || options.flushPolicy.EveryEvaluations > 0 {
+		r = newFlushResolver(p.tracked, options.flushPolicy, r)
+	}
+	if options.flushPolicy.Interval > 0 {
+		p.flusher = newIntervalFlusher(p.tracked, options.flushPolicy.Interval)
+	}
+	if options.remoteDataConfig != nil {
+		p.remoteData = newRemoteDataLoader(p.tracked, *options.remoteDataConfig)
+		r = newRemoteDataResolver(p.remoteData, r)
+	}
+	if options.warehouseConfig != nil {
+		p.warehouse = newWarehouseAudiences(p.tracked, *options.warehouseConfig)
+		r = newWarehouseResolver(p.warehouse, r)
+	}
+	// The legal consent is applied first, so the remote data and the warehouse audiences of visitors
+	// without consent aren't fetched.
+	r = newConsentResolver(p.tracked, options.consentAttribute, r)
+	if options.identityMappingIndex != nil {
+		r = newIdentityResolver(options.identityMappingIndex, r)
+	}
+	r = newTransactionResolver(p.tracked, options.consentAttribute, r)
+	r = newTargetingKeyResolver(p.targetingKeys, r)
+	r = newPeekResolver(r)
+	p.resolver = newShutdownResolver(r, p.isShutDown)
+	client.OnUpdateConfiguration(p.onConfigurationUpdate)
+	return p
 }
 
 // Metadata returns the metadata of the provider.
@@ -38,9 +199,12 @@ func (p *kameleoonProvider) Metadata() openfeature.Metadata {
 func (p *kameleoonProvider) BooleanEvaluation(
 	ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext,
 ) openfeature.BoolResolutionDetail {
-	result, err, variant := p.resolver.Resolve(ctx, flag, defaultValue, evalCtx)
-	providerResDetail := createProviderResolutionDetail(err, variant)
-	boolResult, _ := result.(bool)
+	if p.strictBoolean {
+		ctx = withStrictBoolean(ctx)
+	}
+	res := resolveDetail(p.resolver, ctx, flag, defaultValue, evalCtx)
+	providerResDetail := createProviderResolutionDetail(res)
+	boolResult, _ := res.value.(bool)
 	return openfeature.BoolResolutionDetail{
 		Value:                    boolResult,
 		ProviderResolutionDetail: providerResDetail,
@@ -51,9 +215,12 @@ func (p *kameleoonProvider) BooleanEvaluation(
 func (p *kameleoonProvider) StringEvaluation(
 	ctx context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext,
 ) openfeature.StringResolutionDetail {
-	result, err, variant := p.resolver.Resolve(ctx, flag, defaultValue, evalCtx)
-	providerResDetail := createProviderResolutionDetail(err, variant)
-	stringResult, _ := result.(string)
+	if _, ok := p.variationKeyFlags[flag]; ok {
+		evalCtx = withVariationKey(evalCtx)
+	}
+	res := resolveDetail(p.resolver, ctx, flag, defaultValue, evalCtx)
+	providerResDetail := createProviderResolutionDetail(res)
+	stringResult, _ := res.value.(string)
 	return openfeature.StringResolutionDetail{
 		Value:                    stringResult,
 		ProviderResolutionDetail: providerResDetail,
@@ -64,9 +231,9 @@ func (p *kameleoonProvider) StringEvaluation(
 func (p *kameleoonProvider) FloatEvaluation(
 	ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext,
 ) openfeature.FloatResolutionDetail {
-	result, err, variant := p.resolver.Resolve(ctx, flag, defaultValue, evalCtx)
-	providerResDetail := createProviderResolutionDetail(err, variant)
-	floatResult, _ := result.(float64)
+	res := resolveDetail(p.resolver, ctx, flag, defaultValue, evalCtx)
+	providerResDetail := createProviderResolutionDetail(res)
+	floatResult, _ := res.value.(float64)
 	return openfeature.FloatResolutionDetail{
 		Value:                    floatResult,
 		ProviderResolutionDetail: providerResDetail,
@@ -77,9 +244,9 @@ func (p *kameleoonProvider) FloatEvaluation(
 func (p *kameleoonProvider) IntEvaluation(
 	ctx context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext,
 ) openfeature.IntResolutionDetail {
-	result, err, variant := p.resolver.Resolve(ctx, flag, defaultValue, evalCtx)
-	providerResDetail := createProviderResolutionDetail(err, variant)
-	intResult, _ := result.(int64)
+	res := resolveDetail(p.resolver, ctx, flag, defaultValue, evalCtx)
+	providerResDetail := createProviderResolutionDetail(res)
+	intResult, _ := res.value.(int64)
 	return openfeature.IntResolutionDetail{
 		Value:                    intResult,
 		ProviderResolutionDetail: providerResDetail,
@@ -89,10 +256,10 @@ func (p *kameleoonProvider) IntEvaluation(
 // ObjectEvaluation returns an object flag
 func (p *kameleoonProvider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{},
 	evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
-	result, err, variant := p.resolver.Resolve(ctx, flag, defaultValue, evalCtx)
-	providerResDetail := createProviderResolutionDetail(err, variant)
+	res := resolveDetail(p.resolver, ctx, flag, defaultValue, evalCtx)
+	providerResDetail := createProviderResolutionDetail(res)
 	return openfeature.InterfaceResolutionDetail{
-		Value:                    result,
+		Value:                    res.value,
 		ProviderResolutionDetail: providerResDetail,
 	}
 }
@@ -100,40 +267,100 @@ func (p *kameleoonProvider) ObjectEvaluation(ctx context.Context, flag string, d
 // Init initializes the provider.
 func (p *kameleoonProvider) Init(evaluationContext openfeature.EvaluationContext) error {
 	err := p.client.WaitInit()
+	if err == nil && p.readiness != nil {
+		p.readiness.setReady()
+	}
 	return err
 }
 
-// Shutdown stops the client.
+// Shutdown flushes the pending visitor data and releases the client, see ShutdownWithReport.
 func (p *kameleoonProvider) Shutdown() {
-	kameleoon.KameleoonClientFactory.Forget(p.siteCode)
+	p.ShutdownWithReport()
 }
 
 // Status returns the current state of the provider.
 func (p *kameleoonProvider) Status() openfeature.State {
+	if p.isShutDown() {
+		return openfeature.NotReadyState
+	}
 	if err := p.Init(openfeature.EvaluationContext{}); err != nil {
 		return openfeature.NotReadyState
 	}
+	if p.isStale() {
+		return openfeature.StaleState
+	}
 	return openfeature.ReadyState
 }
 
-// GetClient returns an instance of KameleoonClient SDK.
+// onConfigurationUpdate is called by the KameleoonClient when a new configuration is applied.
+func (p *kameleoonProvider) onConfigurationUpdate() {
+	if p.readiness != nil {
+		p.readiness.setReady()
+	}
+	if p.cache != nil {
+		p.cache.clear()
+	}
+	p.emit(openfeature.ProviderConfigChange, "Kameleoon configuration was updated")
+	if bc, ok := p.client.(*bootstrapClient); ok && bc.stale && bc.isLive() &&
+		atomic.CompareAndSwapInt32(&p.ready, 0, 1) {
+		p.emit(openfeature.ProviderReady, "Kameleoon provider switched to the fresh configuration")
+	}
+	if p.persister != nil {
+		go p.persister.update()
+	}
+}
+
+// onOverrideFileChange is called when the override file is reloaded.
+func (p *kameleoonProvider) onOverrideFileChange() {
+	if p.cache != nil {
+		p.cache.clear()
+	}
+	p.emit(openfeature.ProviderConfigChange, "Kameleoon override file was reloaded")
+}
+
+// isStale reports whether flags are served from the stored configuration.
+func (p *kameleoonProvider) isStale() bool {
+	bc, ok := p.client.(*bootstrapClient)
+	return ok && bc.stale && !bc.isLive()
+}
+
+// EventChannel returns the channel of events emitted by the provider.
+func (p *kameleoonProvider) EventChannel() <-chan openfeature.Event {
+	return p.events
+}
+
+// emit sends the event to the event channel without blocking, the event is dropped if nobody reads it.
+func (p *kameleoonProvider) emit(eventType openfeature.EventType, message string) {
+	event := openfeature.Event{
+		ProviderName:         META_NAME,
+		EventType:            eventType,
+		ProviderEventDetails: openfeature.ProviderEventDetails{Message: message},
+	}
+	select {
+	case p.events <- event:
+	default:
+	}
+}
+
+// GetClient returns an instance of KameleoonClient SDK. A client shared with other providers is returned
+// as created by KameleoonClientFactory, its configuration update handler is set by the provider and must
+// not be replaced.
 func (p *kameleoonProvider) GetClient() kameleoon.KameleoonClient {
+	if shared, ok := p.client.(*sharedClient); ok {
+		return shared.KameleoonClient
+	}
 	return p.client
 }
 
-// createProviderResolutionDetail creates a ProviderResolutionDetail based on the given error and variant.
-func createProviderResolutionDetail(
-	err *openfeature.ResolutionError, variant string) openfeature.ProviderResolutionDetail {
-	var providerResDetail openfeature.ProviderResolutionDetail
-	if err == nil {
-		providerResDetail = openfeature.ProviderResolutionDetail{
-			Variant: variant,
-		}
-	} else {
-		providerResDetail = openfeature.ProviderResolutionDetail{
-			ResolutionError: *err,
-			Variant:         variant,
-		}
+// createProviderResolutionDetail creates a ProviderResolutionDetail based on the given resolution.
+func createProviderResolutionDetail(res resolution) openfeature.ProviderResolutionDetail {
+	providerResDetail := openfeature.ProviderResolutionDetail{
+		Variant:      res.variant,
+		Reason:       res.reason,
+		FlagMetadata: res.metadata,
+	}
+	if res.err != nil {
+		providerResDetail.ResolutionError = *res.err
 	}
 	return providerResDetail
 }
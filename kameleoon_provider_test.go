@@ -2,7 +2,10 @@ package kameleoon
 
 import (
 	"context"
+
 	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
 	"github.com/open-feature/go-sdk/openfeature"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -215,3 +218,35 @@ func TestShutdown_ForgetSiteCode(t *testing.T) {
 	assert.Same(t, clientToCheck, clientFirst)
 	assert.NotSame(t, clientFirst, clientSecond)
 }
+
+func TestNewKameleoonProviderWithClient_EvaluatesWithFakeClient(t *testing.T) {
+	// Arrange
+	client := kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "recommendations",
+		DefaultVariation: "control",
+		Variations: []kameleoontest.Variation{
+			{Key: "control", Variables: map[string]interface{}{"count": 5}},
+			{Key: "treatment", Variables: map[string]interface{}{"count": 10}},
+		},
+	})
+	client.ForceVariation("visitor", "recommendations", "treatment")
+	provider := NewKameleoonProviderWithClient("siteCode", client)
+	evalContext := openfeature.FlattenedContext{
+		"targetingKey": "visitor",
+		Data.Type.CustomData: map[string]interface{}{
+			Data.CustomDataType.Index:  1,
+			Data.CustomDataType.Values: "premium",
+		},
+	}
+
+	// Act
+	result := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+	provider.Shutdown()
+
+	// Assert
+	assert.Equal(t, 10.0, result.Value)
+	assert.Equal(t, "treatment", result.Variant)
+	data := client.VisitorData("visitor")
+	assert.Len(t, data, 1)
+	assert.Equal(t, 1, data[0].(*types.CustomData).ID())
+}
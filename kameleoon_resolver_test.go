@@ -215,3 +215,55 @@ func TestResolve_ReturnsResultDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestResolve_FeatureEnvironmentDisabled_ReturnsDefaultWithDisabledReason(t *testing.T) {
+	// Arrange
+	flagKey := "testFlag"
+	visitorCode := "testVisitor"
+	defaultValue := 42
+
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("AddData", visitorCode, []types.Data(nil)).Return(nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, flagKey, []bool(nil)).
+		Return("", errs.NewFeatureEnvironmentDisabled(flagKey, "production"))
+
+	resolver := newKameleoonResolver(clientMock)
+	evalContext := openfeature.FlattenedContext{
+		"targetingKey": visitorCode,
+	}
+
+	// Act
+	result := resolver.resolveDetail(context.Background(), flagKey, defaultValue, evalContext)
+
+	// Assert
+	assert.Equal(t, defaultValue, result.value)
+	assert.Nil(t, result.err)
+	assert.Equal(t, "off", result.variant)
+	assert.Equal(t, openfeature.DisabledReason, result.reason)
+}
+
+func TestResolve_OffVariationWithoutVariable_ReturnsDefaultWithDefaultReason(t *testing.T) {
+	// Arrange
+	flagKey := "testFlag"
+	visitorCode := "testVisitor"
+	defaultValue := 42
+
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("AddData", visitorCode, []types.Data(nil)).Return(nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, flagKey, []bool(nil)).Return("off", nil)
+	clientMock.On("GetFeatureVariationVariables", flagKey, "off").Return(map[string]interface{}{}, nil)
+
+	resolver := newKameleoonResolver(clientMock)
+	evalContext := openfeature.FlattenedContext{
+		"targetingKey": visitorCode,
+	}
+
+	// Act
+	result := resolver.resolveDetail(context.Background(), flagKey, defaultValue, evalContext)
+
+	// Assert
+	assert.Equal(t, defaultValue, result.value)
+	assert.Nil(t, result.err)
+	assert.Equal(t, "off", result.variant)
+	assert.Equal(t, openfeature.DefaultReason, result.reason)
+}
@@ -0,0 +1,388 @@
+// Package kameleoontest provides a fake KameleoonClient to unit test code which depends on feature flags
+// served by the Kameleoon provider, without any network access.
+package kameleoontest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/errs"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/Kameleoon/client-go/v3/utils"
+	"github.com/valyala/fasthttp"
+)
+
+var _ kameleoon.KameleoonClient = (*Client)(nil)
+
+// AddDataCall is a recorded call of AddData.
+type AddDataCall struct {
+	VisitorCode string
+	Data        []types.Data
+}
+
+// Exposure is a recorded exposure of a visitor to a variation, tracked by the Kameleoon SDK when
+// GetFeatureVariationKey, GetFeatureVariable or IsFeatureActive assigns the variation.
+type Exposure struct {
+	VisitorCode  string
+	FeatureKey   string
+	VariationKey string
+}
+
+// Client is a fake KameleoonClient serving the declared feature flags. It returns the same errors as
+// the Kameleoon SDK for unknown feature flags, variations and variables, disabled environments and invalid
+// visitor codes. Methods which require Kameleoon servers return empty results.
+//
+// Client is safe for concurrent use.
+type Client struct {
+	mu          sync.Mutex
+	features    map[string]feature
+	forced      map[string]map[string]string
+	addDataCall []AddDataCall
+	exposures   []Exposure
+	handler     func()
+}
+
+// feature is a declared feature flag with normalized variables.
+type feature struct {
+	Feature
+	variations map[string]map[string]interface{}
+}
+
+// NewClient creates a new instance of Client serving the given feature flags.
+// It panics if a variable value can't be represented in JSON.
+func NewClient(features ...Feature) *Client {
+	c := &Client{
+		forced: make(map[string]map[string]string),
+	}
+	c.features = makeFeatures(features)
+	return c
+}
+
+// NewClientFromYAML creates a new instance of Client serving the feature flags declared in the YAML file.
+func NewClientFromYAML(path string) (*Client, error) {
+	features, err := LoadFeatures(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(features...), nil
+}
+
+func makeFeatures(features []Feature) map[string]feature {
+	result := make(map[string]feature, len(features))
+	for _, f := range features {
+		variations := map[string]map[string]interface{}{VariationOff: {}}
+		for _, variation := range f.Variations {
+			variables, err := normalizeVariables(variation.Variables)
+			if err != nil {
+				panic(err)
+			}
+			variations[variation.Key] = variables
+		}
+		if f.DefaultVariation == "" {
+			f.DefaultVariation = VariationOff
+		}
+		result[f.Key] = feature{Feature: f, variations: variations}
+	}
+	return result
+}
+
+// SetFeatures replaces the served feature flags and calls the handler registered by OnUpdateConfiguration,
+// as the Kameleoon SDK does when a new configuration is applied.
+func (c *Client) SetFeatures(features ...Feature) {
+	c.mu.Lock()
+	c.features = makeFeatures(features)
+	handler := c.handler
+	c.mu.Unlock()
+	if handler != nil {
+		handler()
+	}
+}
+
+// ForceVariation makes the feature flag serve the variation to the visitor.
+func (c *Client) ForceVariation(visitorCode string, featureKey string, variationKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.forced[visitorCode] == nil {
+		c.forced[visitorCode] = make(map[string]string)
+	}
+	c.forced[visitorCode][featureKey] = variationKey
+}
+
+// AddDataCalls returns the recorded calls of AddData in order.
+func (c *Client) AddDataCalls() []AddDataCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]AddDataCall(nil), c.addDataCall...)
+}
+
+// Exposures returns the recorded exposures in order.
+func (c *Client) Exposures() []Exposure {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Exposure(nil), c.exposures...)
+}
+
+// VisitorData returns all data added for the visitor in order.
+func (c *Client) VisitorData(visitorCode string) []types.Data {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var data []types.Data
+	for _, call := range c.addDataCall {
+		if call.VisitorCode == visitorCode {
+			data = append(data, call.Data...)
+		}
+	}
+	return data
+}
+
+// Reset forgets the recorded calls, the exposures and the forced variations.
+func (c *Client) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addDataCall = nil
+	c.exposures = nil
+	c.forced = make(map[string]map[string]string)
+}
+
+// variationKey returns the variation of the feature flag for the visitor.
+func (c *Client) variationKey(visitorCode string, featureKey string) (feature, string, error) {
+	if err := utils.ValidateVisitorCode(visitorCode); err != nil {
+		return feature{}, VariationOff, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, ok := c.features[featureKey]
+	if !ok {
+		return f, VariationOff, errs.NewFeatureNotFound(featureKey)
+	}
+	if f.EnvironmentDisabled {
+		return f, VariationOff, errs.NewFeatureEnvironmentDisabled(featureKey, "")
+	}
+	if variationKey, ok := c.forced[visitorCode][featureKey]; ok {
+		return f, variationKey, nil
+	}
+	if variationKey, ok := f.Visitors[visitorCode]; ok {
+		return f, variationKey, nil
+	}
+	return f, f.DefaultVariation, nil
+}
+
+// trackedVariationKey returns the variation of the feature flag for the visitor and records the exposure.
+func (c *Client) trackedVariationKey(visitorCode string, featureKey string) (feature, string, error) {
+	f, variationKey, err := c.variationKey(visitorCode, featureKey)
+	if err != nil {
+		return f, variationKey, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.exposures = append(c.exposures, Exposure{
+		VisitorCode:  visitorCode,
+		FeatureKey:   featureKey,
+		VariationKey: variationKey,
+	})
+	return f, variationKey, nil
+}
+
+func (c *Client) WaitInit() error {
+	return nil
+}
+
+// GetVisitorCode returns the default visitor code if any, or a generated one.
+func (c *Client) GetVisitorCode(
+	request *fasthttp.Request, response *fasthttp.Response, defaultVisitorCode ...string,
+) (string, error) {
+	if len(defaultVisitorCode) > 0 {
+		return defaultVisitorCode[0], utils.ValidateVisitorCode(defaultVisitorCode[0])
+	}
+	return utils.GenerateVisitorCode(), nil
+}
+
+func (c *Client) SetLegalConsent(visitorCode string, consent bool, response ...*fasthttp.Response) error {
+	return utils.ValidateVisitorCode(visitorCode)
+}
+
+// AddData records the call.
+func (c *Client) AddData(visitorCode string, allData ...types.Data) error {
+	if err := utils.ValidateVisitorCode(visitorCode); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addDataCall = append(c.addDataCall, AddDataCall{
+		VisitorCode: visitorCode,
+		Data:        append([]types.Data(nil), allData...),
+	})
+	return nil
+}
+
+// TrackConversion records the conversion as a call of AddData.
+func (c *Client) TrackConversion(visitorCode string, goalID int, isUniqueIdentifier ...bool) error {
+	return c.AddData(visitorCode, types.NewConversion(goalID))
+}
+
+// TrackConversionRevenue records the conversion as a call of AddData.
+func (c *Client) TrackConversionRevenue(
+	visitorCode string, goalID int, revenue float64, isUniqueIdentifier ...bool,
+) error {
+	return c.AddData(visitorCode, types.NewConversionWithRevenue(goalID, revenue))
+}
+
+func (c *Client) FlushVisitor(visitorCode string, isUniqueIdentifier ...bool) error {
+	return utils.ValidateVisitorCode(visitorCode)
+}
+
+func (c *Client) FlushVisitorInstantly(visitorCode string) error {
+	return utils.ValidateVisitorCode(visitorCode)
+}
+
+func (c *Client) FlushAll(instant ...bool) {
+}
+
+// GetFeatureVariationKey returns the variation of the visitor and records the exposure.
+func (c *Client) GetFeatureVariationKey(
+	visitorCode string, featureKey string, isUniqueIdentifier ...bool,
+) (string, error) {
+	_, variationKey, err := c.trackedVariationKey(visitorCode, featureKey)
+	return variationKey, err
+}
+
+// GetFeatureVariable returns the variable of the visitor's variation and records the exposure.
+func (c *Client) GetFeatureVariable(
+	visitorCode string, featureKey string, variableKey string, isUniqueIdentifier ...bool,
+) (interface{}, error) {
+	f, variationKey, err := c.trackedVariationKey(visitorCode, featureKey)
+	if err != nil {
+		return nil, err
+	}
+	variables, ok := f.variations[variationKey]
+	if !ok {
+		return nil, errs.NewFeatureVariationNotFound(featureKey, variationKey)
+	}
+	value, ok := variables[variableKey]
+	if !ok {
+		return nil, errs.NewFeatureVariableNotFound(featureKey, variationKey, variableKey)
+	}
+	return value, nil
+}
+
+// IsFeatureActive reports whether the visitor's variation isn't "off" and records the exposure.
+func (c *Client) IsFeatureActive(visitorCode string, featureKey string, isUniqueIdentifier ...bool) (bool, error) {
+	_, variationKey, err := c.trackedVariationKey(visitorCode, featureKey)
+	if err != nil {
+		return false, err
+	}
+	return variationKey != VariationOff, nil
+}
+
+func (c *Client) GetFeatureVariationVariables(featureKey string, variationKey string) (map[string]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, ok := c.features[featureKey]
+	if !ok {
+		return nil, errs.NewFeatureNotFound(featureKey)
+	}
+	if f.EnvironmentDisabled {
+		return nil, errs.NewFeatureEnvironmentDisabled(featureKey, "")
+	}
+	variables, ok := f.variations[variationKey]
+	if !ok {
+		return nil, errs.NewFeatureVariationNotFound(featureKey, variationKey)
+	}
+	result := make(map[string]interface{}, len(variables))
+	for key, value := range variables {
+		result[key] = value
+	}
+	return result, nil
+}
+
+func (c *Client) GetRemoteData(key string, timeout ...time.Duration) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *Client) GetVisitorWarehouseAudience(
+	params kameleoon.VisitorWarehouseAudienceParams,
+) (*types.CustomData, error) {
+	return nil, nil
+}
+
+func (c *Client) GetVisitorWarehouseAudienceWithOptParams(
+	visitorCode string, customDataIndex int, params ...kameleoon.VisitorWarehouseAudienceOptParams,
+) (*types.CustomData, error) {
+	return nil, nil
+}
+
+func (c *Client) GetRemoteVisitorData(
+	visitorCode string, addData bool, timeout ...time.Duration,
+) ([]types.Data, error) {
+	return []types.Data{}, utils.ValidateVisitorCode(visitorCode)
+}
+
+func (c *Client) GetRemoteVisitorDataWithOptParams(
+	visitorCode string, addData bool, filter types.RemoteVisitorDataFilter,
+	params ...kameleoon.RemoteVisitorDataOptParams,
+) ([]types.Data, error) {
+	return []types.Data{}, utils.ValidateVisitorCode(visitorCode)
+}
+
+func (c *Client) GetRemoteVisitorDataWithFilter(
+	visitorCode string, addData bool, filter types.RemoteVisitorDataFilter,
+	params ...kameleoon.RemoteVisitorDataOptParams,
+) ([]types.Data, error) {
+	return []types.Data{}, utils.ValidateVisitorCode(visitorCode)
+}
+
+// OnUpdateConfiguration registers the handler called by SetFeatures.
+func (c *Client) OnUpdateConfiguration(handler func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handler = handler
+}
+
+func (c *Client) GetFeatureList() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(c.features))
+	for key := range c.features {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *Client) GetActiveFeatureListForVisitor(visitorCode string) ([]string, error) {
+	activeFeatures, err := c.GetActiveFeatures(visitorCode)
+	if err != nil {
+		return []string{}, err
+	}
+	keys := make([]string, 0, len(activeFeatures))
+	for key := range activeFeatures {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (c *Client) GetActiveFeatures(visitorCode string) (map[string]types.Variation, error) {
+	if err := utils.ValidateVisitorCode(visitorCode); err != nil {
+		return nil, err
+	}
+	activeFeatures := make(map[string]types.Variation)
+	for _, featureKey := range c.GetFeatureList() {
+		f, variationKey, err := c.variationKey(visitorCode, featureKey)
+		if err != nil || variationKey == VariationOff {
+			continue
+		}
+		variables := make(map[string]types.Variable)
+		for key, value := range f.variations[variationKey] {
+			variables[key] = types.Variable{Key: key, Value: value}
+		}
+		activeFeatures[featureKey] = types.Variation{Key: variationKey, Variables: variables}
+	}
+	return activeFeatures, nil
+}
+
+func (c *Client) GetEngineTrackingCode(visitorCode string) string {
+	return ""
+}
@@ -0,0 +1,140 @@
+package kameleoontest
+
+import (
+	"testing"
+
+	"github.com/Kameleoon/client-go/v3/errs"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) *Client {
+	client, err := NewClientFromYAML("testdata/features.yaml")
+	require.NoError(t, err)
+	return client
+}
+
+func TestNewClientFromYAML_ServesDeclaredFeatures(t *testing.T) {
+	// Arrange
+	client := newTestClient(t)
+
+	// Act
+	premiumVariation, premiumErr := client.GetFeatureVariationKey("premiumVisitor", "banner")
+	regularVariation, regularErr := client.GetFeatureVariationKey("regularVisitor", "banner")
+	title, titleErr := client.GetFeatureVariable("premiumVisitor", "banner", "title")
+	variables, variablesErr := client.GetFeatureVariationVariables("recommendations", "treatment")
+
+	// Assert
+	assert.NoError(t, premiumErr)
+	assert.Equal(t, "on", premiumVariation)
+	assert.NoError(t, regularErr)
+	assert.Equal(t, "off", regularVariation)
+	assert.NoError(t, titleErr)
+	assert.Equal(t, "Premium offer", title)
+	assert.NoError(t, variablesErr)
+	assert.Equal(t, map[string]interface{}{
+		"count":  10.0,
+		"layout": map[string]interface{}{"columns": 3.0},
+	}, variables)
+	assert.Equal(t, []string{"banner", "legacy", "recommendations"}, client.GetFeatureList())
+}
+
+func TestClient_ReturnsSdkErrors(t *testing.T) {
+	// Arrange
+	client := newTestClient(t)
+
+	// Act
+	_, notFoundErr := client.GetFeatureVariationKey("visitor", "unknown")
+	_, disabledErr := client.GetFeatureVariationKey("visitor", "legacy")
+	_, invalidErr := client.GetFeatureVariationKey("", "banner")
+	_, variationErr := client.GetFeatureVariationVariables("banner", "unknown")
+	_, variableErr := client.GetFeatureVariable("visitor", "recommendations", "unknown")
+
+	// Assert
+	assert.IsType(t, &errs.FeatureNotFound{}, notFoundErr)
+	assert.IsType(t, &errs.FeatureEnvironmentDisabled{}, disabledErr)
+	assert.IsType(t, &errs.VisitorCodeInvalid{}, invalidErr)
+	assert.IsType(t, &errs.FeatureVariationNotFound{}, variationErr)
+	assert.IsType(t, &errs.FeatureVariableNotFound{}, variableErr)
+}
+
+func TestClient_ForceVariation(t *testing.T) {
+	// Arrange
+	client := NewClient(Feature{
+		Key:              "recommendations",
+		DefaultVariation: "control",
+		Variations: []Variation{
+			{Key: "control", Variables: map[string]interface{}{"count": 5}},
+			{Key: "treatment", Variables: map[string]interface{}{"count": 10}},
+		},
+	})
+
+	// Act
+	client.ForceVariation("visitor", "recommendations", "treatment")
+	forced, _ := client.GetFeatureVariable("visitor", "recommendations", "count")
+	other, _ := client.GetFeatureVariable("otherVisitor", "recommendations", "count")
+	activeFeatures, err := client.GetActiveFeatures("visitor")
+
+	// Assert
+	assert.Equal(t, 10.0, forced)
+	assert.Equal(t, 5.0, other)
+	assert.NoError(t, err)
+	assert.Equal(t, "treatment", activeFeatures["recommendations"].Key)
+}
+
+func TestClient_RecordsAddDataCalls(t *testing.T) {
+	// Arrange
+	client := NewClient()
+	customData := types.NewCustomData(1, "premium")
+
+	// Act
+	_ = client.AddData("visitor", customData)
+	_ = client.AddData("otherVisitor", types.NewCustomData(2, "basic"))
+	_ = client.TrackConversion("visitor", 42)
+
+	// Assert
+	assert.Len(t, client.AddDataCalls(), 3)
+	assert.Equal(t, AddDataCall{VisitorCode: "visitor", Data: []types.Data{customData}}, client.AddDataCalls()[0])
+	visitorData := client.VisitorData("visitor")
+	assert.Len(t, visitorData, 2)
+	assert.Equal(t, customData, visitorData[0])
+	assert.Equal(t, 42, visitorData[1].(*types.Conversion).GoalId())
+}
+
+func TestClient_RecordsExposures(t *testing.T) {
+	// Arrange
+	client := NewClient(
+		Feature{Key: "banner", DefaultVariation: "on", Variations: []Variation{{Key: "on"}}},
+		Feature{Key: "legacy", EnvironmentDisabled: true},
+	)
+
+	// Act
+	_, _ = client.GetFeatureVariationKey("visitor", "banner")
+	_, _ = client.IsFeatureActive("otherVisitor", "banner")
+	_, _ = client.GetFeatureVariationKey("visitor", "legacy")
+	_, _ = client.GetActiveFeatures("visitor")
+	exposures := client.Exposures()
+	client.Reset()
+
+	// Assert
+	assert.Equal(t, []Exposure{
+		{VisitorCode: "visitor", FeatureKey: "banner", VariationKey: "on"},
+		{VisitorCode: "otherVisitor", FeatureKey: "banner", VariationKey: "on"},
+	}, exposures)
+	assert.Empty(t, client.Exposures())
+}
+
+func TestClient_SetFeaturesCallsUpdateHandler(t *testing.T) {
+	// Arrange
+	client := NewClient()
+	updated := false
+	client.OnUpdateConfiguration(func() { updated = true })
+
+	// Act
+	client.SetFeatures(Feature{Key: "banner"})
+
+	// Assert
+	assert.True(t, updated)
+	assert.Equal(t, []string{"banner"}, client.GetFeatureList())
+}
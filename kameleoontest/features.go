@@ -0,0 +1,80 @@
+package kameleoontest
+
+import (
+	"encoding/json"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// VariationOff is the variation served to visitors for whom a feature flag is off.
+const VariationOff = "off"
+
+// Feature declares a feature flag served by the fake client.
+type Feature struct {
+	Key string `yaml:"key"`
+	// DefaultVariation is served to visitors without a forced variation, VariationOff if empty.
+	DefaultVariation string `yaml:"defaultVariation"`
+	// Variations of the feature flag, VariationOff without variables is always available.
+	Variations []Variation `yaml:"variations"`
+	// Visitors maps visitor codes to their forced variations.
+	Visitors map[string]string `yaml:"visitors"`
+	// EnvironmentDisabled makes evaluations fail as for a feature flag disabled in the environment.
+	EnvironmentDisabled bool `yaml:"environmentDisabled"`
+}
+
+// Variation declares a variation of a feature flag and its variables.
+//
+// Variable values are normalized the way Kameleoon serves them in JSON, so numbers become float64
+// and objects become map[string]interface{}.
+type Variation struct {
+	Key       string                 `yaml:"key"`
+	Variables map[string]interface{} `yaml:"variables"`
+}
+
+// featuresFile is the layout of a YAML file declaring feature flags.
+type featuresFile struct {
+	Features []Feature `yaml:"features"`
+}
+
+// ParseFeatures parses feature flags declared in YAML:
+//
+//	features:
+//	  - key: banner
+//	    defaultVariation: off
+//	    variations:
+//	      - key: on
+//	        variables:
+//	          title: Premium offer
+//	    visitors:
+//	      premiumVisitor: on
+func ParseFeatures(content []byte) ([]Feature, error) {
+	var file featuresFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	return file.Features, nil
+}
+
+// LoadFeatures reads feature flags declared in the YAML file.
+func LoadFeatures(path string) ([]Feature, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFeatures(content)
+}
+
+// normalizeVariables converts the variable values to the types decoded from the JSON served by Kameleoon.
+func normalizeVariables(variables map[string]interface{}) (map[string]interface{}, error) {
+	normalized := make(map[string]interface{}, len(variables))
+	if len(variables) == 0 {
+		return normalized, nil
+	}
+	content, err := json.Marshal(variables)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(content, &normalized)
+	return normalized, err
+}
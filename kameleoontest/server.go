@@ -0,0 +1,396 @@
+package kameleoontest
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+)
+
+// Endpoint identifies an endpoint of the Kameleoon backend served by Server.
+type Endpoint string
+
+const (
+	// EndpointConfiguration serves the configuration of the site.
+	EndpointConfiguration Endpoint = "configuration"
+	// EndpointToken issues OAuth access tokens.
+	EndpointToken Endpoint = "token"
+	// EndpointTracking receives tracked visitor data.
+	EndpointTracking Endpoint = "tracking"
+	// EndpointVisitorData serves remote visitor data, always empty.
+	EndpointVisitorData Endpoint = "visitorData"
+	// EndpointRemoteData serves remote data, always empty.
+	EndpointRemoteData Endpoint = "remoteData"
+	// EndpointEvents streams configuration updates with server-sent events.
+	EndpointEvents Endpoint = "events"
+)
+
+// configurationUpdateEvent is the server-sent event making the Kameleoon SDK fetch the configuration.
+const configurationUpdateEvent = "configuration-update-event"
+
+// kameleoonHosts are the host names of the server certificate, the ones called by the Kameleoon SDK.
+var kameleoonHosts = []string{"kameleoon.com", "*.kameleoon.com", "*.kameleoon.eu", "*.kameleoon.io", "*.kameleoon.net"}
+
+// accessTokenLifetime is the lifetime in seconds of issued access tokens.
+const accessTokenLifetime = 3600
+
+// TrackingRequest is a recorded request of the tracking endpoint.
+type TrackingRequest struct {
+	Query url.Values
+	// Lines of the body, one per tracked event.
+	Lines []string
+}
+
+// Server is a fake Kameleoon backend for integration tests of the full provider without network access.
+//
+// The Kameleoon SDK calls hard-coded Kameleoon hosts, so Server acts as an HTTP proxy which tunnels every
+// connection to its backend: set KameleoonClientConfig.Network.ProxyURL to Server.ProxyURL or use ClientConfig.
+// The backend serves the configuration, OAuth token, tracking, visitor data and server-sent events endpoints
+// with configurable latency and failures, under a self-signed certificate for the Kameleoon hosts which
+// Client trusts.
+//
+// The Kameleoon SDK streams real-time configuration updates with a net/http client it creates itself and
+// which can't be configured, so the SDK can't be routed to the server-sent events endpoint: it receives
+// the events sent by PublishConfigurationUpdate only through Client.
+//
+// Server is safe for concurrent use.
+type Server struct {
+	// ProxyURL is the URL to set as KameleoonClientConfig.Network.ProxyURL.
+	ProxyURL string
+
+	siteCode    string
+	certificate *x509.Certificate
+	proxy       *httptest.Server
+	backend     *httptest.Server
+	done        chan struct{}
+
+	mu            sync.Mutex
+	configuration []byte
+	latency       map[Endpoint]time.Duration
+	failures      map[Endpoint]int
+	requests      map[Endpoint]int
+	tracking      []TrackingRequest
+	subscribers   map[chan int64]struct{}
+	tunnels       map[net.Conn]struct{}
+}
+
+// NewServer creates and starts a new instance of Server serving the configuration for the site code.
+// The configuration has the JSON format served by Kameleoon. Close the server when the test finishes.
+func NewServer(siteCode string, configuration []byte) *Server {
+	s := &Server{
+		siteCode:      siteCode,
+		configuration: configuration,
+		done:          make(chan struct{}),
+		latency:       make(map[Endpoint]time.Duration),
+		failures:      make(map[Endpoint]int),
+		requests:      make(map[Endpoint]int),
+		subscribers:   make(map[chan int64]struct{}),
+		tunnels:       make(map[net.Conn]struct{}),
+	}
+	certificate, err := newKameleoonCertificate()
+	if err != nil {
+		panic(fmt.Sprintf("kameleoontest: failed to create the server certificate: %v", err))
+	}
+	s.certificate = certificate.Leaf
+	s.backend = httptest.NewUnstartedServer(http.HandlerFunc(s.serveBackend))
+	s.backend.TLS = &tls.Config{Certificates: []tls.Certificate{certificate}}
+	s.backend.StartTLS()
+	s.proxy = httptest.NewServer(http.HandlerFunc(s.serveProxy))
+	s.ProxyURL = s.proxy.Listener.Addr().String()
+	return s
+}
+
+// Close stops the server and closes all open connections.
+func (s *Server) Close() {
+	close(s.done)
+	s.mu.Lock()
+	for conn := range s.tunnels {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.proxy.Close()
+	s.backend.Close()
+}
+
+// ClientConfig returns a KameleoonClientConfig routing the Kameleoon SDK to the server.
+func (s *Server) ClientConfig() *kameleoon.KameleoonClientConfig {
+	return &kameleoon.KameleoonClientConfig{
+		Network:      kameleoon.NetworkConfig{ProxyURL: s.ProxyURL},
+		ClientID:     "clientId",
+		ClientSecret: "clientSecret",
+	}
+}
+
+// SetConfiguration replaces the served configuration. Clients fetch it on their next refresh,
+// PublishConfigurationUpdate makes clients in real-time update mode fetch it immediately.
+func (s *Server) SetConfiguration(configuration []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configuration = configuration
+}
+
+// PublishConfigurationUpdate sends a configuration update event to all clients streaming server-sent events.
+func (s *Server) PublishConfigurationUpdate() {
+	ts := time.Now().UnixNano() / int64(time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- ts:
+		default:
+		}
+	}
+}
+
+// SetLatency delays every response of the endpoint by the duration.
+func (s *Server) SetLatency(endpoint Endpoint, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency[endpoint] = latency
+}
+
+// SetFailure makes the endpoint respond with the status code, zero restores successful responses.
+func (s *Server) SetFailure(endpoint Endpoint, statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[endpoint] = statusCode
+}
+
+// Requests returns the number of requests received by the endpoint.
+func (s *Server) Requests(endpoint Endpoint) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[endpoint]
+}
+
+// TrackingRequests returns the recorded requests of the tracking endpoint in order.
+func (s *Server) TrackingRequests() []TrackingRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]TrackingRequest(nil), s.tracking...)
+}
+
+// TrackedLines returns the lines of all recorded tracking requests in order.
+func (s *Server) TrackedLines() []string {
+	var lines []string
+	for _, request := range s.TrackingRequests() {
+		lines = append(lines, request.Lines...)
+	}
+	return lines
+}
+
+// Client returns an HTTP client routed to the server which trusts its certificate, for code calling
+// the Kameleoon endpoints with its own client. The transport is dedicated to the client, so nothing
+// process-wide such as http.DefaultTransport is changed.
+func (s *Server) Client() *http.Client {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.certificate)
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyURL(&url.URL{Scheme: "http", Host: s.ProxyURL}),
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}
+}
+
+// serveProxy tunnels CONNECT requests to the backend.
+func (s *Server) serveProxy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	upstream, err := net.Dial("tcp", s.backend.Listener.Addr().String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "hijacking isn't supported", http.StatusInternalServerError)
+		return
+	}
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	s.mu.Lock()
+	s.tunnels[conn] = struct{}{}
+	s.tunnels[upstream] = struct{}{}
+	s.mu.Unlock()
+	if _, err = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+		s.closeTunnel(conn, upstream)
+		return
+	}
+	go func() {
+		_, _ = io.Copy(upstream, buffered)
+		s.closeTunnel(conn, upstream)
+	}()
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+		s.closeTunnel(conn, upstream)
+	}()
+}
+
+func (s *Server) closeTunnel(conns ...net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range conns {
+		conn.Close()
+		delete(s.tunnels, conn)
+	}
+}
+
+// endpoint returns the endpoint of the backend request.
+func (s *Server) endpoint(r *http.Request) (Endpoint, bool) {
+	switch r.URL.Path {
+	case "/oauth/token":
+		return EndpointToken, true
+	case "/visit/events":
+		return EndpointTracking, true
+	case "/visit/visitor":
+		return EndpointVisitorData, true
+	case "/map/map":
+		return EndpointRemoteData, true
+	case "/sse":
+		return EndpointEvents, true
+	case "/" + s.siteCode:
+		return EndpointConfiguration, true
+	}
+	return "", false
+}
+
+// serveBackend serves the Kameleoon endpoints, applying the configured latency and failures.
+func (s *Server) serveBackend(w http.ResponseWriter, r *http.Request) {
+	endpoint, ok := s.endpoint(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	s.mu.Lock()
+	s.requests[endpoint]++
+	latency, failure := s.latency[endpoint], s.failures[endpoint]
+	s.mu.Unlock()
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-s.done:
+			return
+		}
+	}
+	if failure != 0 {
+		http.Error(w, http.StatusText(failure), failure)
+		return
+	}
+	switch endpoint {
+	case EndpointConfiguration:
+		s.mu.Lock()
+		configuration := s.configuration
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(configuration)
+	case EndpointToken:
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token","expires_in":%d}`, accessTokenLifetime)
+	case EndpointTracking:
+		s.recordTracking(r)
+	case EndpointVisitorData:
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	case EndpointRemoteData:
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`null`))
+	case EndpointEvents:
+		s.streamEvents(w, r)
+	}
+}
+
+func (s *Server) recordTracking(r *http.Request) {
+	request := TrackingRequest{Query: r.URL.Query()}
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			request.Lines = append(request.Lines, line)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tracking = append(s.tracking, request)
+}
+
+// streamEvents sends configuration update events until the client disconnects or the server is closed.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming isn't supported", http.StatusInternalServerError)
+		return
+	}
+	events := make(chan int64, 16)
+	s.mu.Lock()
+	s.subscribers[events] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, events)
+		s.mu.Unlock()
+	}()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case ts := <-events:
+			_, _ = fmt.Fprintf(w, "event: %s\ndata: {\"ts\":%d}\n\n", configurationUpdateEvent, ts)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// newKameleoonCertificate creates a self-signed certificate for the Kameleoon hosts.
+func newKameleoonCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"kameleoontest"}},
+		DNSNames:              kameleoonHosts,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
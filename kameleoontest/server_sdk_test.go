@@ -0,0 +1,63 @@
+//go:build !race
+
+// The Kameleoon SDK has a data race in WaitInit, between the goroutine loading the configuration and
+// the waiting one, so the tests running a real Kameleoon client are excluded from race builds.
+
+package kameleoontest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKameleoonClient(t *testing.T, siteCode string, server *Server) kameleoon.KameleoonClient {
+	config := server.ClientConfig()
+	config.TrackingInterval = 100 * time.Millisecond
+	client, err := kameleoon.KameleoonClientFactory.Create(siteCode, config)
+	require.NoError(t, err)
+	t.Cleanup(func() { kameleoon.KameleoonClientFactory.Forget(siteCode) })
+	return client
+}
+
+func TestServer_ServesConfigurationAndReceivesTracking(t *testing.T) {
+	// Arrange
+	siteCode := "serverSiteCode"
+	server := newTestServer(t, siteCode)
+	client := newTestKameleoonClient(t, siteCode, server)
+
+	// Act
+	initErr := client.WaitInit()
+	_ = client.AddData("visitor", types.NewCustomData(1, "premium"))
+	variation, variationErr := client.GetFeatureVariationKey("visitor", "banner")
+	client.FlushAll(true)
+	require.Eventually(t, func() bool { return len(server.TrackedLines()) > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// Assert
+	assert.NoError(t, initErr)
+	assert.NoError(t, variationErr)
+	assert.Equal(t, "on", variation)
+	assert.Equal(t, 1, server.Requests(EndpointConfiguration))
+	assert.Equal(t, siteCode, server.TrackingRequests()[0].Query.Get("siteCode"))
+	assert.Contains(t, server.TrackedLines()[0], "visitorCode=visitor")
+}
+
+func TestServer_ConfigurationFailure_FailsInit(t *testing.T) {
+	// Arrange
+	siteCode := "failingSiteCode"
+	server := newTestServer(t, siteCode)
+	server.SetFailure(EndpointConfiguration, http.StatusInternalServerError)
+	client := newTestKameleoonClient(t, siteCode, server)
+
+	// Act
+	err := client.WaitInit()
+
+	// Assert
+	assert.Error(t, err)
+	assert.GreaterOrEqual(t, server.Requests(EndpointConfiguration), 1)
+}
@@ -0,0 +1,91 @@
+package kameleoontest
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, siteCode string) *Server {
+	configuration, err := os.ReadFile("testdata/configuration.json")
+	require.NoError(t, err)
+	server := NewServer(siteCode, configuration)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestServer_Client_ServesConfigurationOfKameleoonHost(t *testing.T) {
+	// Arrange
+	siteCode := "clientSiteCode"
+	server := newTestServer(t, siteCode)
+
+	// Act
+	response, err := server.Client().Get("https://sdk-config.kameleoon.eu/" + siteCode)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, readErr := io.ReadAll(response.Body)
+
+	// Assert
+	assert.NoError(t, readErr)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, string(body), `"featureKey"`)
+	assert.Equal(t, 1, server.Requests(EndpointConfiguration))
+}
+
+func TestServer_Latency_DelaysResponses(t *testing.T) {
+	// Arrange
+	siteCode := "slowSiteCode"
+	server := newTestServer(t, siteCode)
+	server.SetLatency(EndpointConfiguration, 200*time.Millisecond)
+	start := time.Now()
+
+	// Act
+	response, err := server.Client().Get("https://sdk-config.kameleoon.eu/" + siteCode)
+
+	// Assert
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestServer_Failure_RespondsWithStatusCode(t *testing.T) {
+	// Arrange
+	siteCode := "failingClientSiteCode"
+	server := newTestServer(t, siteCode)
+	server.SetFailure(EndpointTracking, http.StatusServiceUnavailable)
+
+	// Act
+	response, err := server.Client().Post("https://data.kameleoon.io/visit/events", "text/plain",
+		strings.NewReader("visitorCode=visitor"))
+
+	// Assert
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	assert.Empty(t, server.TrackingRequests())
+}
+
+func TestServer_PublishConfigurationUpdate_StreamsEvent(t *testing.T) {
+	// Arrange
+	server := newTestServer(t, "eventsSiteCode")
+	response, err := server.Client().Get("https://events.kameleoon.com:8110/sse")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	reader := bufio.NewReader(response.Body)
+
+	// Act
+	server.PublishConfigurationUpdate()
+	line, readErr := reader.ReadString('\n')
+
+	// Assert
+	assert.NoError(t, readErr)
+	assert.Equal(t, "event: "+configurationUpdateEvent+"\n", line)
+}
@@ -0,0 +1,343 @@
+package kameleoon
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/configuration"
+	"github.com/Kameleoon/client-go/v3/errs"
+	"github.com/Kameleoon/client-go/v3/managers/data"
+	"github.com/Kameleoon/client-go/v3/network/cookie"
+	"github.com/Kameleoon/client-go/v3/storage"
+	"github.com/Kameleoon/client-go/v3/targeting"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/Kameleoon/client-go/v3/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// ErrOfflineMode is returned by the methods of a local client which require access to Kameleoon servers.
+var ErrOfflineMode = errors.New("the operation isn't available for a local Kameleoon configuration")
+
+// localClient is a KameleoonClient which evaluates feature flags from a local configuration
+// without any network access. Added data is kept in memory and is never sent.
+type localClient struct {
+	dataManager      data.DataManager
+	visitorManager   storage.VisitorManager
+	targetingManager targeting.TargetingManager
+	cookieManager    cookie.CookieManager
+}
+
+// loadLocalConfiguration reads a Kameleoon configuration in the JSON format served by Kameleoon.
+func loadLocalConfiguration(path string) (configuration.Configuration, error) {
+	var config configuration.Configuration
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	err = parseLocalConfiguration(content, &config)
+	return config, err
+}
+
+// parseLocalConfiguration parses a Kameleoon configuration in the JSON format served by Kameleoon.
+func parseLocalConfiguration(content []byte, config *configuration.Configuration) error {
+	if err := json.Unmarshal(content, config); err != nil {
+		configErr := errs.NewConfigError("Local configuration is invalid: " + err.Error())
+		return &configErr
+	}
+	return nil
+}
+
+// newLocalClient creates a new instance of localClient for the given configuration.
+func newLocalClient(config configuration.Configuration, environment string) *localClient {
+	dm := data.NewDataManagerImpl(configuration.NewDataFile(config, environment))
+	vm := storage.NewVisitorManagerImpl(dm, kameleoon.DefaultSessionDuration)
+	return &localClient{
+		dataManager:      dm,
+		visitorManager:   vm,
+		targetingManager: targeting.NewTargetingManager(dm, vm),
+		cookieManager:    cookie.NewCookieManagerImpl(dm, ""),
+	}
+}
+
+// close releases the visitor storage of the client.
+func (c *localClient) close() {
+	c.visitorManager.Close()
+}
+
+func (c *localClient) WaitInit() error {
+	return nil
+}
+
+func (c *localClient) GetVisitorCode(
+	request *fasthttp.Request, response *fasthttp.Response, defaultVisitorCode ...string,
+) (string, error) {
+	return c.cookieManager.GetOrAdd(request, response, defaultVisitorCode...)
+}
+
+func (c *localClient) SetLegalConsent(visitorCode string, consent bool, response ...*fasthttp.Response) error {
+	if err := utils.ValidateVisitorCode(visitorCode); err != nil {
+		return err
+	}
+	c.visitorManager.GetOrCreateVisitor(visitorCode).SetLegalConsent(consent)
+	if len(response) > 0 {
+		c.cookieManager.Update(visitorCode, consent, response[0])
+	}
+	return nil
+}
+
+func (c *localClient) AddData(visitorCode string, allData ...types.Data) error {
+	if err := utils.ValidateVisitorCode(visitorCode); err != nil {
+		return err
+	}
+	c.visitorManager.AddData(visitorCode, allData...)
+	return nil
+}
+
+func (c *localClient) TrackConversion(visitorCode string, goalID int, isUniqueIdentifier ...bool) error {
+	c.setUniqueIdentifier(visitorCode, isUniqueIdentifier)
+	return c.AddData(visitorCode, types.NewConversion(goalID))
+}
+
+func (c *localClient) TrackConversionRevenue(
+	visitorCode string, goalID int, revenue float64, isUniqueIdentifier ...bool,
+) error {
+	c.setUniqueIdentifier(visitorCode, isUniqueIdentifier)
+	return c.AddData(visitorCode, types.NewConversionWithRevenue(goalID, revenue))
+}
+
+func (c *localClient) FlushVisitor(visitorCode string, isUniqueIdentifier ...bool) error {
+	return utils.ValidateVisitorCode(visitorCode)
+}
+
+func (c *localClient) FlushVisitorInstantly(visitorCode string) error {
+	return utils.ValidateVisitorCode(visitorCode)
+}
+
+func (c *localClient) FlushAll(instant ...bool) {
+}
+
+func (c *localClient) GetFeatureVariationKey(
+	visitorCode string, featureKey string, isUniqueIdentifier ...bool,
+) (string, error) {
+	c.setUniqueIdentifier(visitorCode, isUniqueIdentifier)
+	_, variationKey, err := c.getFeatureVariationKey(visitorCode, featureKey)
+	return variationKey, err
+}
+
+func (c *localClient) GetFeatureVariable(
+	visitorCode string, featureKey string, variableKey string, isUniqueIdentifier ...bool,
+) (interface{}, error) {
+	c.setUniqueIdentifier(visitorCode, isUniqueIdentifier)
+	featureFlag, variationKey, err := c.getFeatureVariationKey(visitorCode, featureKey)
+	if err != nil {
+		return nil, err
+	}
+	variation, ok := featureFlag.GetVariationByKey(variationKey)
+	if !ok {
+		return nil, errs.NewFeatureVariationNotFound(featureKey, variationKey)
+	}
+	variable, ok := variation.GetVariableByKey(variableKey)
+	if !ok {
+		return nil, errs.NewFeatureVariableNotFound(featureKey, variationKey, variableKey)
+	}
+	return parseVariableValue(variable), nil
+}
+
+func (c *localClient) IsFeatureActive(
+	visitorCode string, featureKey string, isUniqueIdentifier ...bool,
+) (bool, error) {
+	variationKey, err := c.GetFeatureVariationKey(visitorCode, featureKey, isUniqueIdentifier...)
+	if _, ok := err.(*errs.FeatureEnvironmentDisabled); ok {
+		return false, nil
+	}
+	return err == nil && variationKey != string(types.VariationOff), err
+}
+
+func (c *localClient) GetFeatureVariationVariables(
+	featureKey string, variationKey string,
+) (map[string]interface{}, error) {
+	featureFlag, err := c.dataManager.DataFile().GetFeatureFlag(featureKey)
+	if err != nil {
+		return nil, err
+	}
+	variation, ok := featureFlag.GetVariationByKey(variationKey)
+	if !ok {
+		return nil, errs.NewFeatureVariationNotFound(featureKey, variationKey)
+	}
+	variables := make(map[string]interface{}, len(variation.Variables))
+	for i := range variation.Variables {
+		variables[variation.Variables[i].Key] = parseVariableValue(&variation.Variables[i])
+	}
+	return variables, nil
+}
+
+func (c *localClient) GetRemoteData(string, ...time.Duration) ([]byte, error) {
+	return nil, ErrOfflineMode
+}
+
+func (c *localClient) GetVisitorWarehouseAudience(
+	kameleoon.VisitorWarehouseAudienceParams,
+) (*types.CustomData, error) {
+	return nil, ErrOfflineMode
+}
+
+func (c *localClient) GetVisitorWarehouseAudienceWithOptParams(
+	string, int, ...kameleoon.VisitorWarehouseAudienceOptParams,
+) (*types.CustomData, error) {
+	return nil, ErrOfflineMode
+}
+
+func (c *localClient) GetRemoteVisitorData(string, bool, ...time.Duration) ([]types.Data, error) {
+	return nil, ErrOfflineMode
+}
+
+func (c *localClient) GetRemoteVisitorDataWithOptParams(
+	string, bool, types.RemoteVisitorDataFilter, ...kameleoon.RemoteVisitorDataOptParams,
+) ([]types.Data, error) {
+	return nil, ErrOfflineMode
+}
+
+func (c *localClient) GetRemoteVisitorDataWithFilter(
+	string, bool, types.RemoteVisitorDataFilter, ...kameleoon.RemoteVisitorDataOptParams,
+) ([]types.Data, error) {
+	return nil, ErrOfflineMode
+}
+
+// OnUpdateConfiguration does nothing, the local configuration is never updated.
+func (c *localClient) OnUpdateConfiguration(func()) {
+}
+
+func (c *localClient) GetFeatureList() []string {
+	featureFlags := c.dataManager.DataFile().GetFeatureFlags()
+	keys := make([]string, 0, len(featureFlags))
+	for key := range featureFlags {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (c *localClient) GetActiveFeatureListForVisitor(visitorCode string) ([]string, error) {
+	activeFeatures, err := c.GetActiveFeatures(visitorCode)
+	if err != nil {
+		return []string{}, err
+	}
+	keys := make([]string, 0, len(activeFeatures))
+	for key := range activeFeatures {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (c *localClient) GetActiveFeatures(visitorCode string) (map[string]types.Variation, error) {
+	if err := utils.ValidateVisitorCode(visitorCode); err != nil {
+		return nil, err
+	}
+	activeFeatures := make(map[string]types.Variation)
+	for key, featureFlag := range c.dataManager.DataFile().GetFeatureFlags() {
+		if !featureFlag.GetEnvironmentEnabled() {
+			continue
+		}
+		varByExp, rule := c.calculateVariationRuleForFeature(visitorCode, featureFlag)
+		variationKey := featureFlag.GetVariationKey(varByExp, rule)
+		if variationKey == string(types.VariationOff) {
+			continue
+		}
+		variables := make(map[string]types.Variable)
+		if variation, ok := featureFlag.GetVariationByKey(variationKey); ok {
+			for i := range variation.Variables {
+				variable := variation.Variables[i]
+				variables[variable.Key] = types.Variable{
+					Key: variable.Key, Type: variable.Type, Value: parseVariableValue(&variable),
+				}
+			}
+		}
+		activeVariation := types.Variation{Key: variationKey, Variables: variables}
+		if varByExp != nil {
+			activeVariation.VariationID = varByExp.VariationID
+		}
+		if rule != nil {
+			activeVariation.ExperimentID = &rule.GetRuleBase().ExperimentId
+		}
+		activeFeatures[key] = activeVariation
+	}
+	return activeFeatures, nil
+}
+
+func (c *localClient) GetEngineTrackingCode(string) string {
+	return ""
+}
+
+// getFeatureVariationKey calculates the variation of the feature flag and assigns it to the visitor.
+func (c *localClient) getFeatureVariationKey(
+	visitorCode string, featureKey string,
+) (types.FeatureFlag, string, error) {
+	if err := utils.ValidateVisitorCode(visitorCode); err != nil {
+		return nil, string(types.VariationOff), err
+	}
+	featureFlag, err := c.dataManager.DataFile().GetFeatureFlag(featureKey)
+	if err != nil {
+		return nil, string(types.VariationOff), err
+	}
+	varByExp, rule := c.calculateVariationRuleForFeature(visitorCode, featureFlag)
+	if rule != nil && varByExp != nil && varByExp.VariationID != nil {
+		c.visitorManager.GetOrCreateVisitor(visitorCode).AssignVariation(types.NewAssignedVariation(
+			rule.GetRuleBase().ExperimentId, *varByExp.VariationID, rule.GetRuleBase().Type))
+	}
+	return featureFlag, featureFlag.GetVariationKey(varByExp, rule), nil
+}
+
+// calculateVariationRuleForFeature finds the rule and variation of the feature flag for the visitor
+// the same way the Kameleoon SDK does.
+func (c *localClient) calculateVariationRuleForFeature(
+	visitorCode string, featureFlag types.FeatureFlag,
+) (*types.VariationByExposition, types.Rule) {
+	codeForHash := visitorCode
+	if visitor := c.visitorManager.GetVisitor(visitorCode); visitor != nil && visitor.MappingIdentifier() != nil {
+		codeForHash = *visitor.MappingIdentifier()
+	}
+	for _, rule := range featureFlag.GetRules() {
+		ruleBase := rule.GetRuleBase()
+		if !c.targetingManager.CheckTargeting(visitorCode, ruleBase.ExperimentId, rule) {
+			continue
+		}
+		if utils.GetHashDoubleRule(codeForHash, ruleBase.Id, ruleBase.RespoolTime) <= ruleBase.Exposition {
+			if rule.IsTargetDeliveryType() {
+				if len(ruleBase.VariationByExposition) > 0 {
+					return &ruleBase.VariationByExposition[0], rule
+				}
+				return nil, rule
+			}
+			hashVariation := utils.GetHashDoubleRule(codeForHash, ruleBase.ExperimentId, ruleBase.RespoolTime)
+			if variation := rule.GetVariationByHash(hashVariation); variation != nil {
+				return variation, rule
+			}
+		}
+		if rule.IsTargetDeliveryType() {
+			break
+		}
+	}
+	return nil, nil
+}
+
+func (c *localClient) setUniqueIdentifier(visitorCode string, isUniqueIdentifier []bool) {
+	if len(isUniqueIdentifier) > 0 && utils.ValidateVisitorCode(visitorCode) == nil {
+		c.visitorManager.AddData(visitorCode, types.NewUniqueIdentifier(isUniqueIdentifier[0]))
+	}
+}
+
+// parseVariableValue returns the value of the variable, decoding JSON variables.
+func parseVariableValue(variable *types.Variable) interface{} {
+	if variable.Type != "JSON" {
+		return variable.Value
+	}
+	var value interface{}
+	if s, ok := variable.Value.(string); ok {
+		if err := json.Unmarshal([]byte(s), &value); err != nil {
+			return nil
+		}
+	}
+	return value
+}
@@ -0,0 +1,117 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kameleoon/client-go/v3/errs"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigurationPath = "testdata/configuration.json"
+
+func newTestLocalClient(t *testing.T) *localClient {
+	config, err := loadLocalConfiguration(testConfigurationPath)
+	require.NoError(t, err)
+	client := newLocalClient(config, "")
+	t.Cleanup(client.close)
+	return client
+}
+
+func TestLocalClient_EvaluatesTargetingFromConfiguration(t *testing.T) {
+	// Arrange
+	client := newTestLocalClient(t)
+	_ = client.AddData("premiumVisitor", types.NewCustomData(1, "premium"))
+
+	// Act
+	premiumVariation, premiumErr := client.GetFeatureVariationKey("premiumVisitor", "banner")
+	regularVariation, regularErr := client.GetFeatureVariationKey("regularVisitor", "banner")
+
+	// Assert
+	assert.NoError(t, premiumErr)
+	assert.Equal(t, "on", premiumVariation)
+	assert.NoError(t, regularErr)
+	assert.Equal(t, "off", regularVariation)
+}
+
+func TestLocalClient_ReturnsSdkErrors(t *testing.T) {
+	// Arrange
+	client := newTestLocalClient(t)
+
+	// Act
+	_, notFoundErr := client.GetFeatureVariationKey("visitor", "unknown")
+	_, disabledErr := client.GetFeatureVariationKey("visitor", "legacy")
+	_, invalidErr := client.GetFeatureVariationKey("", "banner")
+
+	// Assert
+	assert.IsType(t, &errs.FeatureNotFound{}, notFoundErr)
+	assert.IsType(t, &errs.FeatureEnvironmentDisabled{}, disabledErr)
+	assert.IsType(t, &errs.VisitorCodeInvalid{}, invalidErr)
+}
+
+func TestLocalClient_GetActiveFeatures(t *testing.T) {
+	// Arrange
+	client := newTestLocalClient(t)
+
+	// Act
+	activeFeatures, err := client.GetActiveFeatures("visitor")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"banner", "recommendations", "layout", "legacy"}, client.GetFeatureList())
+	assert.Len(t, activeFeatures, 2)
+	assert.Equal(t, "control", activeFeatures["recommendations"].Key)
+	assert.Equal(t, map[string]interface{}{"columns": 3.0}, activeFeatures["layout"].Variables["settings"].Value)
+}
+
+func TestLocalClient_RemoteMethodsReturnOfflineError(t *testing.T) {
+	// Arrange
+	client := newTestLocalClient(t)
+
+	// Act
+	_, err := client.GetRemoteVisitorData("visitor", true)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrOfflineMode)
+}
+
+func TestNewOfflineKameleoonProvider_EvaluatesFlagsWithoutNetwork(t *testing.T) {
+	// Arrange
+	provider, err := NewOfflineKameleoonProvider("siteCode", testConfigurationPath)
+	require.NoError(t, err)
+	defer provider.Shutdown()
+	evalContext := openfeature.FlattenedContext{
+		"targetingKey": "premiumVisitor",
+		Data.Type.CustomData: map[string]interface{}{
+			Data.CustomDataType.Index:  1,
+			Data.CustomDataType.Values: "premium",
+		},
+	}
+
+	// Act
+	initErr := provider.Init(openfeature.EvaluationContext{})
+	title := provider.StringEvaluation(context.Background(), "banner", "", evalContext)
+	count := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+	layout := provider.ObjectEvaluation(context.Background(), "layout", map[string]interface{}{}, evalContext)
+
+	// Assert
+	assert.NoError(t, initErr)
+	assert.Equal(t, openfeature.ReadyState, provider.Status())
+	assert.Equal(t, "Premium offer", title.Value)
+	assert.Equal(t, "on", title.Variant)
+	assert.Equal(t, 5.0, count.Value)
+	assert.Equal(t, map[string]interface{}{"columns": 3.0}, layout.Value)
+}
+
+func TestNewOfflineKameleoonProvider_InvalidConfiguration_ReturnsError(t *testing.T) {
+	// Act
+	_, missingErr := NewOfflineKameleoonProvider("siteCode", "testdata/missing.json")
+	_, invalidErr := NewOfflineKameleoonProvider("siteCode", "testdata/invalid.json")
+
+	// Assert
+	assert.Error(t, missingErr)
+	assert.Error(t, invalidErr)
+}
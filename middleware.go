@@ -0,0 +1,62 @@
+package kameleoon
+
+import (
+	"net/http"
+
+	"github.com/Kameleoon/client-go/v3/logging"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/valyala/fasthttp"
+)
+
+// VisitorCodeCookie is the cookie holding the Kameleoon visitor code, shared with the Kameleoon SDKs.
+const VisitorCodeCookie = "kameleoonVisitorCode"
+
+// HTTPMiddleware reads the Kameleoon visitor code cookie of the request, or creates one, and serves the request
+// with a transaction context having the visitor code as targeting key. A transaction context set by a former
+// middleware keeps its attributes and its targeting key if any.
+func (p *kameleoonProvider) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(request)
+		response := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseResponse(response)
+		if cookie, err := r.Cookie(VisitorCodeCookie); err == nil {
+			request.Header.SetCookie(VisitorCodeCookie, cookie.Value)
+		}
+		visitorCode, err := p.client.GetVisitorCode(request, response)
+		if err != nil {
+			logging.Warning("Failed to get the Kameleoon visitor code of the request: %s", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		response.Header.VisitAllCookie(func(_, cookie []byte) {
+			w.Header().Add("Set-Cookie", string(cookie))
+		})
+		ctx := WithTransaction(r.Context(), withVisitorCode(TransactionEvaluationContext(r.Context()), visitorCode))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// FastHTTPMiddleware reads the Kameleoon visitor code cookie of the request, or creates one, and stores
+// a transaction context having the visitor code as targeting key in the request context. A transaction context
+// set by a former middleware keeps its attributes and its targeting key if any.
+func (p *kameleoonProvider) FastHTTPMiddleware(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		visitorCode, err := p.client.GetVisitorCode(&ctx.Request, &ctx.Response)
+		if err != nil {
+			logging.Warning("Failed to get the Kameleoon visitor code of the request: %s", err)
+		} else {
+			evalCtx := withVisitorCode(TransactionEvaluationContext(ctx), visitorCode)
+			ctx.SetUserValue(transactionContextUserValue, newTransactionContext(evalCtx))
+		}
+		next(ctx)
+	}
+}
+
+// withVisitorCode returns the evaluation context with the visitor code as targeting key, unless it has one.
+func withVisitorCode(evalCtx openfeature.EvaluationContext, visitorCode string) openfeature.EvaluationContext {
+	if evalCtx.TargetingKey() != "" {
+		return evalCtx
+	}
+	return openfeature.NewEvaluationContext(visitorCode, evalCtx.Attributes())
+}
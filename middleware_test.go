@@ -0,0 +1,104 @@
+package kameleoon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func newMiddlewareTestProvider(t *testing.T) *kameleoonProvider {
+	provider, err := NewOfflineKameleoonProvider("siteCode", testConfigurationPath)
+	require.NoError(t, err)
+	t.Cleanup(provider.Shutdown)
+	return provider
+}
+
+func TestKameleoonProvider_HTTPMiddleware_CreatesVisitorCodeCookie(t *testing.T) {
+	// Arrange
+	provider := newMiddlewareTestProvider(t)
+	var evalCtx openfeature.EvaluationContext
+	var count openfeature.FloatResolutionDetail
+	handler := provider.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		evalCtx = TransactionEvaluationContext(r.Context())
+		count = provider.FloatEvaluation(r.Context(), "recommendations", 0, openfeature.FlattenedContext{})
+	}))
+	recorder := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Assert
+	cookie := recorder.Header().Get("Set-Cookie")
+	assert.NotEmpty(t, evalCtx.TargetingKey())
+	assert.True(t, strings.HasPrefix(cookie, VisitorCodeCookie+"="+evalCtx.TargetingKey()+";"), cookie)
+	assert.NoError(t, count.Error())
+	assert.Equal(t, 5.0, count.Value)
+}
+
+func TestKameleoonProvider_HTTPMiddleware_ReadsVisitorCodeCookie(t *testing.T) {
+	// Arrange
+	provider := newMiddlewareTestProvider(t)
+	var evalCtx openfeature.EvaluationContext
+	handler := provider.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		evalCtx = TransactionEvaluationContext(r.Context())
+	}))
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.AddCookie(&http.Cookie{Name: VisitorCodeCookie, Value: "knownVisitor"})
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	// Assert
+	assert.Equal(t, "knownVisitor", evalCtx.TargetingKey())
+}
+
+func TestKameleoonProvider_FastHTTPMiddleware_StoresEvaluationContext(t *testing.T) {
+	// Arrange
+	provider := newMiddlewareTestProvider(t)
+	var evalCtx openfeature.EvaluationContext
+	var title openfeature.StringResolutionDetail
+	handler := provider.FastHTTPMiddleware(func(ctx *fasthttp.RequestCtx) {
+		evalCtx = TransactionEvaluationContext(ctx)
+		title = provider.StringEvaluation(ctx, "banner", "", openfeature.FlattenedContext{
+			Data.Type.CustomData: map[string]interface{}{
+				Data.CustomDataType.Index:  1,
+				Data.CustomDataType.Values: "premium",
+			},
+		})
+	})
+	var ctx fasthttp.RequestCtx
+	ctx.Request.Header.SetCookie(VisitorCodeCookie, "knownVisitor")
+
+	// Act
+	handler(&ctx)
+
+	// Assert
+	assert.Equal(t, "knownVisitor", evalCtx.TargetingKey())
+	assert.Equal(t, "Premium offer", title.Value)
+}
+
+func TestKameleoonProvider_HTTPMiddleware_KeepsFormerTransactionContext(t *testing.T) {
+	// Arrange
+	provider := newMiddlewareTestProvider(t)
+	var evalCtx openfeature.EvaluationContext
+	handler := provider.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		evalCtx = TransactionEvaluationContext(r.Context())
+	}))
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request = request.WithContext(WithTransaction(request.Context(),
+		openfeature.NewEvaluationContext("", map[string]interface{}{Data.Type.Device: "phone"})))
+	request.AddCookie(&http.Cookie{Name: VisitorCodeCookie, Value: "knownVisitor"})
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	// Assert
+	assert.Equal(t, "knownVisitor", evalCtx.TargetingKey())
+	assert.Equal(t, "phone", evalCtx.Attribute(Data.Type.Device))
+}
@@ -0,0 +1,175 @@
+package kameleoon
+
+import (
+	"context"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/mock"
+	"github.com/valyala/fasthttp"
+)
+
+// MockKameleoonResolver is a mock implementation of the resolver interface.
+type MockKameleoonResolver struct {
+	mock.Mock
+}
+
+func (m *MockKameleoonResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	args := m.Called(ctx, flag, defaultValue, evalCtx)
+	resErr, _ := args.Get(1).(*openfeature.ResolutionError)
+	return args.Get(0), resErr, args.String(2)
+}
+
+// MockKameleoonClient is a mock implementation of the KameleoonClient interface.
+type MockKameleoonClient struct {
+	mock.Mock
+}
+
+func (m *MockKameleoonClient) WaitInit() error {
+	return m.Called().Error(0)
+}
+
+func (m *MockKameleoonClient) GetVisitorCode(
+	request *fasthttp.Request, response *fasthttp.Response, defaultVisitorCode ...string,
+) (string, error) {
+	args := m.Called(request, response, defaultVisitorCode)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockKameleoonClient) SetLegalConsent(
+	visitorCode string, consent bool, response ...*fasthttp.Response,
+) error {
+	return m.Called(visitorCode, consent, response).Error(0)
+}
+
+func (m *MockKameleoonClient) AddData(visitorCode string, allData ...types.Data) error {
+	return m.Called(visitorCode, allData).Error(0)
+}
+
+func (m *MockKameleoonClient) TrackConversion(visitorCode string, goalID int, isUniqueIdentifier ...bool) error {
+	return m.Called(visitorCode, goalID, isUniqueIdentifier).Error(0)
+}
+
+func (m *MockKameleoonClient) TrackConversionRevenue(
+	visitorCode string, goalID int, revenue float64, isUniqueIdentifier ...bool,
+) error {
+	return m.Called(visitorCode, goalID, revenue, isUniqueIdentifier).Error(0)
+}
+
+func (m *MockKameleoonClient) FlushVisitor(visitorCode string, isUniqueIdentifier ...bool) error {
+	return m.Called(visitorCode, isUniqueIdentifier).Error(0)
+}
+
+func (m *MockKameleoonClient) FlushVisitorInstantly(visitorCode string) error {
+	return m.Called(visitorCode).Error(0)
+}
+
+func (m *MockKameleoonClient) FlushAll(instant ...bool) {
+	m.Called(instant)
+}
+
+func (m *MockKameleoonClient) GetFeatureVariationKey(
+	visitorCode string, featureKey string, isUniqueIdentifier ...bool,
+) (string, error) {
+	args := m.Called(visitorCode, featureKey, isUniqueIdentifier)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockKameleoonClient) GetFeatureVariable(
+	visitorCode string, featureKey string, variableKey string, isUniqueIdentifier ...bool,
+) (interface{}, error) {
+	args := m.Called(visitorCode, featureKey, variableKey, isUniqueIdentifier)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockKameleoonClient) IsFeatureActive(
+	visitorCode string, featureKey string, isUniqueIdentifier ...bool,
+) (bool, error) {
+	args := m.Called(visitorCode, featureKey, isUniqueIdentifier)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockKameleoonClient) GetFeatureVariationVariables(
+	featureKey string, variationKey string,
+) (map[string]interface{}, error) {
+	args := m.Called(featureKey, variationKey)
+	variables, _ := args.Get(0).(map[string]interface{})
+	return variables, args.Error(1)
+}
+
+func (m *MockKameleoonClient) GetRemoteData(key string, timeout ...time.Duration) ([]byte, error) {
+	args := m.Called(key, timeout)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+func (m *MockKameleoonClient) GetVisitorWarehouseAudience(
+	params kameleoon.VisitorWarehouseAudienceParams,
+) (*types.CustomData, error) {
+	args := m.Called(params)
+	customData, _ := args.Get(0).(*types.CustomData)
+	return customData, args.Error(1)
+}
+
+func (m *MockKameleoonClient) GetVisitorWarehouseAudienceWithOptParams(
+	visitorCode string, customDataIndex int, params ...kameleoon.VisitorWarehouseAudienceOptParams,
+) (*types.CustomData, error) {
+	args := m.Called(visitorCode, customDataIndex, params)
+	customData, _ := args.Get(0).(*types.CustomData)
+	return customData, args.Error(1)
+}
+
+func (m *MockKameleoonClient) GetRemoteVisitorData(
+	visitorCode string, addData bool, timeout ...time.Duration,
+) ([]types.Data, error) {
+	args := m.Called(visitorCode, addData, timeout)
+	data, _ := args.Get(0).([]types.Data)
+	return data, args.Error(1)
+}
+
+func (m *MockKameleoonClient) GetRemoteVisitorDataWithOptParams(
+	visitorCode string, addData bool, filter types.RemoteVisitorDataFilter,
+	params ...kameleoon.RemoteVisitorDataOptParams,
+) ([]types.Data, error) {
+	args := m.Called(visitorCode, addData, filter, params)
+	data, _ := args.Get(0).([]types.Data)
+	return data, args.Error(1)
+}
+
+func (m *MockKameleoonClient) GetRemoteVisitorDataWithFilter(
+	visitorCode string, addData bool, filter types.RemoteVisitorDataFilter,
+	params ...kameleoon.RemoteVisitorDataOptParams,
+) ([]types.Data, error) {
+	args := m.Called(visitorCode, addData, filter, params)
+	data, _ := args.Get(0).([]types.Data)
+	return data, args.Error(1)
+}
+
+func (m *MockKameleoonClient) OnUpdateConfiguration(handler func()) {
+	m.Called(handler)
+}
+
+func (m *MockKameleoonClient) GetFeatureList() []string {
+	features, _ := m.Called().Get(0).([]string)
+	return features
+}
+
+func (m *MockKameleoonClient) GetActiveFeatureListForVisitor(visitorCode string) ([]string, error) {
+	args := m.Called(visitorCode)
+	features, _ := args.Get(0).([]string)
+	return features, args.Error(1)
+}
+
+func (m *MockKameleoonClient) GetActiveFeatures(visitorCode string) (map[string]types.Variation, error) {
+	args := m.Called(visitorCode)
+	features, _ := args.Get(0).(map[string]types.Variation)
+	return features, args.Error(1)
+}
+
+func (m *MockKameleoonClient) GetEngineTrackingCode(visitorCode string) string {
+	return m.Called(visitorCode).String(0)
+}
@@ -0,0 +1,412 @@
+package kameleoon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const MULTI_SITE_META_NAME = "Kameleoon Multi-Site Provider"
+
+// DefaultSiteAttribute is the context attribute routing evaluations of MultiSiteProvider to a site code.
+const DefaultSiteAttribute = "siteCode"
+
+// SiteMetadataKey is the flag metadata key reporting the site code which served the evaluation.
+const SiteMetadataKey = "siteCode"
+
+// MultiSiteOption configures the routing of MultiSiteProvider.
+type MultiSiteOption func(*multiSiteOptions)
+
+// multiSiteOptions holds the routing settings of MultiSiteProvider.
+type multiSiteOptions struct {
+	siteAttribute string
+	siteValues    map[string]string
+	flagPrefixes  map[string]string
+	defaultSite   string
+	providerOpts  []Option
+}
+
+// WithSiteAttribute routes evaluations by the context attribute, e.g. "domain". The sites map translates
+// attribute values to site codes; if it's nil, the attribute value is the site code itself.
+// Evaluations are routed by the DefaultSiteAttribute attribute if the option isn't used.
+func WithSiteAttribute(attribute string, sites map[string]string) MultiSiteOption {
+	return func(o *multiSiteOptions) {
+		o.siteAttribute = attribute
+		o.siteValues = sites
+	}
+}
+
+// WithFlagPrefix routes evaluations of flags whose key starts with the prefix, e.g. "brandA.", to the site.
+// The prefix is removed from the flag key before the evaluation, even if the evaluation is routed by
+// the site attribute. The longest matching prefix wins.
+func WithFlagPrefix(prefix string, siteCode string) MultiSiteOption {
+	return func(o *multiSiteOptions) {
+		o.flagPrefixes[prefix] = siteCode
+	}
+}
+
+// WithDefaultSite routes evaluations which match no context attribute or flag prefix to the site.
+func WithDefaultSite(siteCode string) MultiSiteOption {
+	return func(o *multiSiteOptions) {
+		o.defaultSite = siteCode
+	}
+}
+
+// WithSiteProviderOptions applies the options to the provider of every site.
+func WithSiteProviderOptions(opts ...Option) MultiSiteOption {
+	return func(o *multiSiteOptions) {
+		o.providerOpts = append(o.providerOpts, opts...)
+	}
+}
+
+// MultiSiteProvider routes evaluations to the Kameleoon providers of several site codes, by a context
+// attribute or by a flag key prefix. Its status and events aggregate the ones of all sites.
+type MultiSiteProvider struct {
+	providers map[string]*kameleoonProvider
+	options   *multiSiteOptions
+	prefixes  []string
+	events    chan openfeature.Event
+	stop      chan struct{}
+	stopOnce  sync.Once
+
+	mu         sync.Mutex
+	siteStates map[string]openfeature.State
+	state      openfeature.State
+}
+
+// NewMultiSiteProvider creates a new instance of MultiSiteProvider with a Kameleoon client for every
+// site code of the sites map.
+func NewMultiSiteProvider(
+	sites map[string]*kameleoon.KameleoonClientConfig, opts ...MultiSiteOption,
+) (*MultiSiteProvider, error) {
+	options := newMultiSiteOptions(opts)
+	providers := make(map[string]*kameleoonProvider, len(sites))
+	for siteCode, config := range sites {
+		provider, err := NewKameleoonProvider(siteCode, config, options.providerOpts...)
+		if err != nil {
+			for _, created := range providers {
+				created.Shutdown()
+			}
+			return nil, err
+		}
+		providers[siteCode] = provider
+	}
+	return newMultiSiteProvider(providers, options), nil
+}
+
+func newMultiSiteOptions(opts []MultiSiteOption) *multiSiteOptions {
+	options := &multiSiteOptions{
+		siteAttribute: DefaultSiteAttribute,
+		flagPrefixes:  make(map[string]string),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(options)
+		}
+	}
+	return options
+}
+
+// newMultiSiteProvider creates a new instance of MultiSiteProvider for the providers and starts forwarding
+// their events.
+func newMultiSiteProvider(providers map[string]*kameleoonProvider, options *multiSiteOptions) *MultiSiteProvider {
+	p := &MultiSiteProvider{
+		providers:  providers,
+		options:    options,
+		events:     make(chan openfeature.Event, eventBufferSize),
+		stop:       make(chan struct{}),
+		siteStates: make(map[string]openfeature.State, len(providers)),
+		state:      openfeature.NotReadyState,
+	}
+	for siteCode := range providers {
+		p.siteStates[siteCode] = openfeature.NotReadyState
+	}
+	for prefix := range options.flagPrefixes {
+		p.prefixes = append(p.prefixes, prefix)
+	}
+	sort.Slice(p.prefixes, func(i, j int) bool { return len(p.prefixes[i]) > len(p.prefixes[j]) })
+	for siteCode, provider := range providers {
+		go p.forwardEvents(siteCode, provider.EventChannel())
+	}
+	return p
+}
+
+// Metadata returns the metadata of the provider.
+func (p *MultiSiteProvider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{
+		Name: MULTI_SITE_META_NAME,
+	}
+}
+
+// Provider returns the provider of the site code, nil if there is none.
+func (p *MultiSiteProvider) Provider(siteCode string) *kameleoonProvider {
+	return p.providers[siteCode]
+}
+
+// route returns the site code and the provider serving the flag, and the flag key for that provider.
+// The site attribute is checked first, then the flag prefixes, then the default site. The flag prefix is
+// removed from the flag key whatever routes the evaluation, so the Kameleoon sites never receive it.
+func (p *MultiSiteProvider) route(
+	flag string, evalCtx openfeature.FlattenedContext,
+) (string, *kameleoonProvider, string, *openfeature.ResolutionError) {
+	prefixSite, flagKey, prefixed := p.siteOfFlagPrefix(flag)
+	if siteCode, ok := p.siteOfAttribute(evalCtx); ok {
+		return siteCode, p.providers[siteCode], flagKey, nil
+	}
+	if prefixed {
+		return prefixSite, p.providers[prefixSite], flagKey, nil
+	}
+	if provider, ok := p.providers[p.options.defaultSite]; ok {
+		return p.options.defaultSite, provider, flag, nil
+	}
+	resError := openfeature.NewInvalidContextResolutionError(
+		fmt.Sprintf("The evaluation of the flag '%s' can't be routed to a Kameleoon site", flag))
+	return "", nil, flag, &resError
+}
+
+// siteOfFlagPrefix returns the site code of the longest flag prefix of the flag and the flag key without
+// the prefix, ok is false if the flag has no prefix of a site.
+func (p *MultiSiteProvider) siteOfFlagPrefix(flag string) (siteCode string, flagKey string, ok bool) {
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(flag, prefix) {
+			siteCode = p.options.flagPrefixes[prefix]
+			if _, ok = p.providers[siteCode]; ok {
+				return siteCode, strings.TrimPrefix(flag, prefix), true
+			}
+		}
+	}
+	return "", flag, false
+}
+
+// siteOfAttribute returns the site code of the site attribute of the context, ok is false if the attribute
+// is missing or matches no site.
+func (p *MultiSiteProvider) siteOfAttribute(evalCtx openfeature.FlattenedContext) (siteCode string, ok bool) {
+	value, _ := evalCtx[p.options.siteAttribute].(string)
+	if value == "" {
+		return "", false
+	}
+	siteCode = value
+	if p.options.siteValues != nil {
+		siteCode = p.options.siteValues[value]
+	}
+	_, ok = p.providers[siteCode]
+	return siteCode, ok
+}
+
+// routingFailure creates the ProviderResolutionDetail of an evaluation which can't be routed.
+func routingFailure(resError *openfeature.ResolutionError) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		ResolutionError: *resError,
+		Reason:          openfeature.ErrorReason,
+	}
+}
+
+// withSite reports the site code in the flag metadata.
+func withSite(detail openfeature.ProviderResolutionDetail, siteCode string) openfeature.ProviderResolutionDetail {
+	metadata := openfeature.FlagMetadata{SiteMetadataKey: siteCode}
+	for key, value := range detail.FlagMetadata {
+		metadata[key] = value
+	}
+	detail.FlagMetadata = metadata
+	return detail
+}
+
+// BooleanEvaluation returns a boolean flag
+func (p *MultiSiteProvider) BooleanEvaluation(
+	ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext,
+) openfeature.BoolResolutionDetail {
+	siteCode, provider, flagKey, resError := p.route(flag, evalCtx)
+	if resError != nil {
+		return openfeature.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: routingFailure(resError)}
+	}
+	detail := provider.BooleanEvaluation(ctx, flagKey, defaultValue, evalCtx)
+	detail.ProviderResolutionDetail = withSite(detail.ProviderResolutionDetail, siteCode)
+	return detail
+}
+
+// StringEvaluation returns a string flag
+func (p *MultiSiteProvider) StringEvaluation(
+	ctx context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext,
+) openfeature.StringResolutionDetail {
+	siteCode, provider, flagKey, resError := p.route(flag, evalCtx)
+	if resError != nil {
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: routingFailure(resError)}
+	}
+	detail := provider.StringEvaluation(ctx, flagKey, defaultValue, evalCtx)
+	detail.ProviderResolutionDetail = withSite(detail.ProviderResolutionDetail, siteCode)
+	return detail
+}
+
+// FloatEvaluation returns a float flag
+func (p *MultiSiteProvider) FloatEvaluation(
+	ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext,
+) openfeature.FloatResolutionDetail {
+	siteCode, provider, flagKey, resError := p.route(flag, evalCtx)
+	if resError != nil {
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: routingFailure(resError)}
+	}
+	detail := provider.FloatEvaluation(ctx, flagKey, defaultValue, evalCtx)
+	detail.ProviderResolutionDetail = withSite(detail.ProviderResolutionDetail, siteCode)
+	return detail
+}
+
+// IntEvaluation returns an int flag
+func (p *MultiSiteProvider) IntEvaluation(
+	ctx context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext,
+) openfeature.IntResolutionDetail {
+	siteCode, provider, flagKey, resError := p.route(flag, evalCtx)
+	if resError != nil {
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: routingFailure(resError)}
+	}
+	detail := provider.IntEvaluation(ctx, flagKey, defaultValue, evalCtx)
+	detail.ProviderResolutionDetail = withSite(detail.ProviderResolutionDetail, siteCode)
+	return detail
+}
+
+// ObjectEvaluation returns an object flag
+func (p *MultiSiteProvider) ObjectEvaluation(
+	ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext,
+) openfeature.InterfaceResolutionDetail {
+	siteCode, provider, flagKey, resError := p.route(flag, evalCtx)
+	if resError != nil {
+		return openfeature.InterfaceResolutionDetail{
+			Value: defaultValue, ProviderResolutionDetail: routingFailure(resError),
+		}
+	}
+	detail := provider.ObjectEvaluation(ctx, flagKey, defaultValue, evalCtx)
+	detail.ProviderResolutionDetail = withSite(detail.ProviderResolutionDetail, siteCode)
+	return detail
+}
+
+// Init initializes the providers of all sites concurrently. The OpenFeature SDK reports the outcome,
+// so the state changes made by Init emit no event.
+func (p *MultiSiteProvider) Init(evaluationContext openfeature.EvaluationContext) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failures []string
+	for siteCode, provider := range p.providers {
+		wg.Add(1)
+		go func(siteCode string, provider *kameleoonProvider) {
+			defer wg.Done()
+			err := provider.Init(evaluationContext)
+			switch {
+			case err != nil:
+				p.setSiteState(siteCode, openfeature.ErrorState)
+				mu.Lock()
+				failures = append(failures, fmt.Sprintf("%s: %s", siteCode, err))
+				mu.Unlock()
+			case provider.isStale():
+				p.setSiteState(siteCode, openfeature.StaleState)
+			default:
+				p.setSiteState(siteCode, openfeature.ReadyState)
+			}
+		}(siteCode, provider)
+	}
+	wg.Wait()
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return fmt.Errorf("Kameleoon sites failed to initialize: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Shutdown stops forwarding events and shuts down the providers of all sites.
+func (p *MultiSiteProvider) Shutdown() {
+	p.stopOnce.Do(func() { close(p.stop) })
+	for siteCode, provider := range p.providers {
+		provider.Shutdown()
+		p.setSiteState(siteCode, openfeature.NotReadyState)
+	}
+}
+
+// Status returns the least ready state of the sites: ERROR, then NOT_READY, then STALE, then READY.
+// It's calculated from the state stored for every site by Init and by the site events, so it never blocks.
+func (p *MultiSiteProvider) Status() openfeature.State {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state
+}
+
+// setSiteState stores the state of the site and returns the combined state of all sites, changed is true
+// if the combined state differs from the former one.
+func (p *MultiSiteProvider) setSiteState(siteCode string, state openfeature.State) (openfeature.State, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.siteStates[siteCode] = state
+	combined := combineSiteStates(p.siteStates)
+	changed := combined != p.state
+	p.state = combined
+	return combined, changed
+}
+
+// combineSiteStates returns the least ready of the states, READY only if all sites are ready.
+func combineSiteStates(states map[string]openfeature.State) openfeature.State {
+	found := make(map[openfeature.State]bool)
+	for _, state := range states {
+		found[state] = true
+	}
+	for _, state := range []openfeature.State{
+		openfeature.ErrorState, openfeature.NotReadyState, openfeature.StaleState,
+	} {
+		if found[state] {
+			return state
+		}
+	}
+	return openfeature.ReadyState
+}
+
+// EventChannel returns the channel of events emitted by the providers of all sites.
+func (p *MultiSiteProvider) EventChannel() <-chan openfeature.Event {
+	return p.events
+}
+
+// forwardEvents forwards the events of the site provider, prefixing their messages with the site code.
+// The READY, STALE and ERROR events update the state of the site and are forwarded only if they change
+// the combined state, as the event of the combined state.
+func (p *MultiSiteProvider) forwardEvents(siteCode string, events <-chan openfeature.Event) {
+	for {
+		select {
+		case event := <-events:
+			if state, ok := eventStates[event.EventType]; ok {
+				combined, changed := p.setSiteState(siteCode, state)
+				if !changed {
+					continue
+				}
+				if event.EventType, ok = stateEvents[combined]; !ok {
+					continue
+				}
+			}
+			event.ProviderName = MULTI_SITE_META_NAME
+			event.Message = fmt.Sprintf("%s: %s", siteCode, event.Message)
+			select {
+			case p.events <- event:
+			default:
+			}
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// eventStates maps the provider events changing the state of a site to that state.
+var eventStates = map[openfeature.EventType]openfeature.State{
+	openfeature.ProviderReady: openfeature.ReadyState,
+	openfeature.ProviderStale: openfeature.StaleState,
+	openfeature.ProviderError: openfeature.ErrorState,
+}
+
+// stateEvents maps the combined states to the events announcing them, NOT_READY has no event.
+var stateEvents = map[openfeature.State]openfeature.EventType{
+	openfeature.ReadyState: openfeature.ProviderReady,
+	openfeature.StaleState: openfeature.ProviderStale,
+	openfeature.ErrorState: openfeature.ProviderError,
+}
+
+func (p *MultiSiteProvider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{}
+}
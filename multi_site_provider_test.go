@@ -0,0 +1,232 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSiteTestProvider(siteCode string, title string) *kameleoonProvider {
+	client := kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "banner",
+		DefaultVariation: "on",
+		Variations: []kameleoontest.Variation{
+			{Key: "on", Variables: map[string]interface{}{"title": title}},
+		},
+	})
+	return NewKameleoonProviderWithClient(siteCode, client)
+}
+
+func newMultiSiteTestProvider(opts ...MultiSiteOption) *MultiSiteProvider {
+	return newMultiSiteProvider(map[string]*kameleoonProvider{
+		"brandA": newSiteTestProvider("brandA", "Brand A"),
+		"brandB": newSiteTestProvider("brandB", "Brand B"),
+	}, newMultiSiteOptions(opts))
+}
+
+func TestMultiSiteProvider_RoutesBySiteAttribute(t *testing.T) {
+	// Arrange
+	provider := newMultiSiteTestProvider()
+
+	// Act
+	brandA := provider.StringEvaluation(context.Background(), "banner", "",
+		openfeature.FlattenedContext{"targetingKey": "visitor", "siteCode": "brandA"})
+	brandB := provider.StringEvaluation(context.Background(), "banner", "",
+		openfeature.FlattenedContext{"targetingKey": "visitor", "siteCode": "brandB"})
+	unknown := provider.StringEvaluation(context.Background(), "banner", "default",
+		openfeature.FlattenedContext{"targetingKey": "visitor", "siteCode": "brandC"})
+
+	// Assert
+	assert.Equal(t, "Brand A", brandA.Value)
+	assert.Equal(t, "brandA", brandA.FlagMetadata[SiteMetadataKey])
+	assert.Equal(t, "Brand B", brandB.Value)
+	assert.Equal(t, "brandB", brandB.FlagMetadata[SiteMetadataKey])
+	assert.Equal(t, "default", unknown.Value)
+	assert.Equal(t, openfeature.ErrorReason, unknown.Reason)
+	assert.Equal(t, openfeature.InvalidContextCode, unknown.ResolutionDetail().ErrorCode)
+}
+
+func TestMultiSiteProvider_RoutesByMappedAttribute(t *testing.T) {
+	// Arrange
+	provider := newMultiSiteTestProvider(WithSiteAttribute("domain", map[string]string{
+		"brand-a.com": "brandA",
+		"brand-b.com": "brandB",
+	}))
+
+	// Act
+	result := provider.StringEvaluation(context.Background(), "banner", "",
+		openfeature.FlattenedContext{"targetingKey": "visitor", "domain": "brand-b.com"})
+
+	// Assert
+	assert.Equal(t, "Brand B", result.Value)
+}
+
+func TestMultiSiteProvider_RoutesByFlagPrefixAndDefaultSite(t *testing.T) {
+	// Arrange
+	provider := newMultiSiteTestProvider(WithFlagPrefix("b.", "brandB"), WithDefaultSite("brandA"))
+
+	// Act
+	prefixed := provider.StringEvaluation(context.Background(), "b.banner", "",
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+	unprefixed := provider.StringEvaluation(context.Background(), "banner", "",
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+	// Assert
+	assert.Equal(t, "Brand B", prefixed.Value)
+	assert.Equal(t, "Brand A", unprefixed.Value)
+}
+
+func TestMultiSiteProvider_NoRoute_ReturnsInvalidContext(t *testing.T) {
+	// Arrange
+	provider := newMultiSiteTestProvider()
+
+	// Act
+	result := provider.BooleanEvaluation(context.Background(), "banner", true,
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+	// Assert
+	assert.True(t, result.Value)
+	assert.Equal(t, openfeature.InvalidContextCode, result.ResolutionDetail().ErrorCode)
+}
+
+func TestMultiSiteProvider_RoutesUnmatchedAttributeByFlagPrefixThenDefaultSite(t *testing.T) {
+	// Arrange
+	provider := newMultiSiteTestProvider(WithFlagPrefix("b.", "brandB"), WithDefaultSite("brandA"))
+	evalContext := openfeature.FlattenedContext{"targetingKey": "visitor", "siteCode": "brandC"}
+
+	// Act
+	prefixed := provider.StringEvaluation(context.Background(), "b.banner", "", evalContext)
+	unprefixed := provider.StringEvaluation(context.Background(), "banner", "", evalContext)
+
+	// Assert
+	assert.Equal(t, "Brand B", prefixed.Value)
+	assert.Equal(t, "brandB", prefixed.FlagMetadata[SiteMetadataKey])
+	assert.Equal(t, "Brand A", unprefixed.Value)
+	assert.Equal(t, "brandA", unprefixed.FlagMetadata[SiteMetadataKey])
+}
+
+func TestMultiSiteProvider_RoutesPrefixedFlagBySiteAttribute_RemovesPrefix(t *testing.T) {
+	// Arrange
+	provider := newMultiSiteTestProvider(WithFlagPrefix("b.", "brandB"))
+
+	// Act
+	result := provider.StringEvaluation(context.Background(), "b.banner", "",
+		openfeature.FlattenedContext{"targetingKey": "visitor", "siteCode": "brandA"})
+
+	// Assert
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "Brand A", result.Value)
+	assert.Equal(t, "brandA", result.FlagMetadata[SiteMetadataKey])
+}
+
+func TestMultiSiteProvider_Status_AggregatesSites(t *testing.T) {
+	// Arrange
+	failing := new(MockKameleoonClient)
+	failing.On("OnUpdateConfiguration", mock.Anything)
+	failing.On("WaitInit").Return(context.DeadlineExceeded)
+	ready := newMultiSiteTestProvider()
+	failed := newMultiSiteProvider(map[string]*kameleoonProvider{
+		"brandA": newSiteTestProvider("brandA", "Brand A"),
+		"brandB": NewKameleoonProviderWithClient("brandB", failing),
+	}, newMultiSiteOptions(nil))
+
+	// Act
+	beforeInit := ready.Status()
+	readyErr := ready.Init(openfeature.EvaluationContext{})
+	readyStatus := ready.Status()
+	failedErr := failed.Init(openfeature.EvaluationContext{})
+	failedStatus := failed.Status()
+
+	// Assert
+	assert.Equal(t, openfeature.NotReadyState, beforeInit)
+	assert.NoError(t, readyErr)
+	assert.Equal(t, openfeature.ReadyState, readyStatus)
+	assert.ErrorContains(t, failedErr, "brandB: ")
+	assert.Equal(t, openfeature.ErrorState, failedStatus)
+}
+
+func TestMultiSiteProvider_Status_DoesNotWaitForInitialization(t *testing.T) {
+	// Arrange
+	blocking := new(MockKameleoonClient)
+	blocking.On("OnUpdateConfiguration", mock.Anything)
+	blocking.On("WaitInit").After(time.Hour).Return(nil)
+	provider := newMultiSiteProvider(map[string]*kameleoonProvider{
+		"brandA": NewKameleoonProviderWithClient("brandA", blocking),
+	}, newMultiSiteOptions(nil))
+	status := make(chan openfeature.State, 1)
+
+	// Act
+	go func() { status <- provider.Status() }()
+
+	// Assert
+	select {
+	case state := <-status:
+		assert.Equal(t, openfeature.NotReadyState, state)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Status blocked")
+	}
+}
+
+func TestMultiSiteProvider_EventChannel_EmitsCombinedStateOnChange(t *testing.T) {
+	// Arrange
+	brandA := newSiteTestProvider("brandA", "Brand A")
+	brandB := newSiteTestProvider("brandB", "Brand B")
+	provider := newMultiSiteProvider(map[string]*kameleoonProvider{"brandA": brandA, "brandB": brandB},
+		newMultiSiteOptions(nil))
+	defer provider.Shutdown()
+	next := func() (openfeature.Event, bool) {
+		select {
+		case event := <-provider.EventChannel():
+			return event, true
+		case <-time.After(100 * time.Millisecond):
+			return openfeature.Event{}, false
+		}
+	}
+
+	// Act
+	brandA.emit(openfeature.ProviderReady, "ready")
+	_, partiallyReady := next()
+	brandB.emit(openfeature.ProviderReady, "ready")
+	allReady, allReadyEmitted := next()
+	brandA.emit(openfeature.ProviderReady, "ready again")
+	_, repeated := next()
+	brandB.emit(openfeature.ProviderStale, "stale")
+	stale, staleEmitted := next()
+
+	// Assert
+	assert.False(t, partiallyReady)
+	assert.True(t, allReadyEmitted)
+	assert.Equal(t, openfeature.ProviderReady, allReady.EventType)
+	assert.Contains(t, allReady.Message, "brandB: ")
+	assert.False(t, repeated)
+	assert.True(t, staleEmitted)
+	assert.Equal(t, openfeature.ProviderStale, stale.EventType)
+	assert.Equal(t, openfeature.StaleState, provider.Status())
+}
+
+func TestMultiSiteProvider_EventChannel_ForwardsSiteEvents(t *testing.T) {
+	// Arrange
+	client := kameleoontest.NewClient()
+	provider := newMultiSiteProvider(map[string]*kameleoonProvider{
+		"brandA": NewKameleoonProviderWithClient("brandA", client),
+	}, newMultiSiteOptions(nil))
+	defer provider.Shutdown()
+
+	// Act
+	client.SetFeatures()
+
+	// Assert
+	select {
+	case event := <-provider.EventChannel():
+		assert.Equal(t, openfeature.ProviderConfigChange, event.EventType)
+		assert.Equal(t, MULTI_SITE_META_NAME, event.ProviderName)
+		assert.Contains(t, event.Message, "brandA: ")
+	case <-time.After(time.Second):
+		assert.Fail(t, "no event forwarded")
+	}
+}
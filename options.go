@@ -0,0 +1,60 @@
+package kameleoon
+
+import (
+	"os"
+	"time"
+)
+
+// Option configures optional behaviour of the Kameleoon provider.
+type Option func(*providerOptions)
+
+// providerOptions holds the optional settings of the Kameleoon provider.
+type providerOptions struct {
+	cacheConfig       *CacheConfig
+	bootstrapPath     string
+	manualLiveSwitch  bool
+	liveRetryInterval time.Duration
+	configStore       ConfigurationStore
+	persister         *configurationPersister
+	readiness         *clientReadiness
+
+	contextOverrides         bool
+	overrideFilePath         string
+	overrideFilePollInterval time.Duration
+
+	shutdownTimeout time.Duration
+	flushPolicy     FlushPolicy
+
+	consentAttribute string
+
+	identityMappingIndex *int
+
+	targetingKeyFallback []string
+	anonymousVisitors    bool
+
+	visitorCodeNormalization VisitorCodeNormalization
+
+	strictBoolean     bool
+	variationKeyFlags map[string]struct{}
+
+	remoteDataConfig *RemoteDataConfig
+	warehouseConfig  *WarehouseConfig
+}
+
+// newProviderOptions applies the given options on top of the default settings.
+func newProviderOptions(opts []Option) *providerOptions {
+	options := &providerOptions{
+		overrideFilePollInterval: overrideFilePollInterval,
+		liveRetryInterval:        liveRetryInterval,
+		shutdownTimeout:          defaultShutdownTimeout,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(options)
+		}
+	}
+	if options.overrideFilePath == "" {
+		options.overrideFilePath = os.Getenv(OverrideFileEnv)
+	}
+	return options
+}
@@ -0,0 +1,242 @@
+package kameleoon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/logging"
+	"github.com/open-feature/go-sdk/openfeature"
+	"gopkg.in/yaml.v3"
+)
+
+// OverrideFileEnv is the environment variable with the path of the override file, used if the provider
+// is created without WithOverrideFile.
+const OverrideFileEnv = "KAMELEOON_OVERRIDE_FILE"
+
+// overrideFilePollInterval is the interval between checks of the override file for changes.
+const overrideFilePollInterval = time.Second
+
+// overrideSourceFile is the override source reported for the overrides of the override file.
+const overrideSourceFile = "file"
+
+// WithOverrideFile makes the provider serve the flags of a local YAML or JSON file before the Kameleoon client:
+//
+//	flags:
+//	  recommendations:
+//	    variation: treatment
+//	    variables:
+//	      count: 10
+//	    visitors:
+//	      qaVisitor:
+//	        variation: control
+//
+// The variation is required for every override, its variables are optional and are read from the Kameleoon
+// client if omitted. A file with an override without variation is rejected. The file is watched for changes
+// and PROVIDER_CONFIGURATION_CHANGED is emitted on reload. A missing file serves no overrides until it's created.
+//
+// WARNING: if this option isn't used, the path is read from the KAMELEOON_OVERRIDE_FILE environment variable
+// in every build, including production ones. Anyone who can set the environment of the process or write
+// the file can then force any variation, so make sure the variable is never set in production.
+func WithOverrideFile(path string) Option {
+	return func(o *providerOptions) {
+		o.overrideFilePath = path
+	}
+}
+
+// fileOverride is the override of a flag declared in the override file.
+type fileOverride struct {
+	Variation string                  `yaml:"variation" json:"variation"`
+	Variables map[string]interface{}  `yaml:"variables" json:"variables"`
+	Visitors  map[string]fileOverride `yaml:"visitors" json:"visitors"`
+}
+
+// overrideFileContent is the layout of the override file.
+type overrideFileContent struct {
+	Flags map[string]fileOverride `yaml:"flags" json:"flags"`
+}
+
+// overrideFile holds the overrides of the override file and reloads them when the file changes.
+type overrideFile struct {
+	path     string
+	onChange func()
+
+	mu      sync.RWMutex
+	content []byte
+	flags   map[string]fileOverride
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// newOverrideFile creates a new instance of overrideFile, loads the file and starts watching it.
+// The onChange function is called after every reload.
+func newOverrideFile(path string, interval time.Duration, onChange func()) *overrideFile {
+	f := &overrideFile{
+		path:     path,
+		onChange: onChange,
+		flags:    map[string]fileOverride{},
+		stop:     make(chan struct{}),
+	}
+	logging.Warning("The Kameleoon override file %s is active, its flags take precedence over Kameleoon", path)
+	if _, err := f.load(); err != nil {
+		logging.Warning("Failed to load the Kameleoon override file %s: %s", path, err)
+	}
+	go f.watch(interval)
+	return f
+}
+
+// load reads the file and reports whether the overrides changed, invalid content keeps the former overrides.
+func (f *overrideFile) load() (bool, error) {
+	content, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		content, err = nil, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	f.mu.RLock()
+	unchanged := bytes.Equal(content, f.content)
+	f.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	flags, err := parseOverrideFile(content)
+	if err != nil {
+		return false, err
+	}
+	f.mu.Lock()
+	f.content, f.flags = content, flags
+	f.mu.Unlock()
+	return true, nil
+}
+
+func (f *overrideFile) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			changed, err := f.load()
+			if err != nil {
+				logging.Warning("Failed to reload the Kameleoon override file %s: %s", f.path, err)
+			} else if changed && f.onChange != nil {
+				f.onChange()
+			}
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+// close stops watching the file.
+func (f *overrideFile) close() {
+	f.stopOnce.Do(func() { close(f.stop) })
+}
+
+// lookup returns the override of the flag for the visitor, a visitor override takes precedence.
+func (f *overrideFile) lookup(flag string, visitorCode string) (fileOverride, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	flagOverride, ok := f.flags[flag]
+	if !ok {
+		return flagOverride, false
+	}
+	if visitorOverride, ok := flagOverride.Visitors[visitorCode]; ok {
+		return visitorOverride, true
+	}
+	return flagOverride, flagOverride.Variation != ""
+}
+
+// parseOverrideFile parses the override file content, YAML being a superset of JSON both formats are accepted.
+// Variable values are normalized to the types decoded from the JSON served by Kameleoon.
+func parseOverrideFile(content []byte) (map[string]fileOverride, error) {
+	var file overrideFileContent
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	normalized, err := json.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+	file = overrideFileContent{}
+	if err = json.Unmarshal(normalized, &file); err != nil {
+		return nil, err
+	}
+	if file.Flags == nil {
+		file.Flags = map[string]fileOverride{}
+	}
+	for flag, flagOverride := range file.Flags {
+		if flagOverride.Variation == "" && flagOverride.Variables != nil {
+			return nil, fmt.Errorf("the override of the flag %s has variables without variation", flag)
+		}
+		for visitorCode, visitorOverride := range flagOverride.Visitors {
+			if visitorOverride.Variation == "" {
+				return nil, fmt.Errorf("the override of the flag %s for the visitor %s has no variation",
+					flag, visitorCode)
+			}
+		}
+	}
+	return file.Flags, nil
+}
+
+// fileOverrideResolver serves the overrides of the override file before the wrapped resolver.
+type fileOverrideResolver struct {
+	client   kameleoon.KameleoonClient
+	file     *overrideFile
+	resolver resolver
+}
+
+// newFileOverrideResolver creates a new instance of fileOverrideResolver.
+func newFileOverrideResolver(
+	client kameleoon.KameleoonClient, file *overrideFile, resolver resolver,
+) *fileOverrideResolver {
+	return &fileOverrideResolver{
+		client:   client,
+		file:     file,
+		resolver: resolver,
+	}
+}
+
+// Resolve returns the override of the file if there is one for the flag, otherwise resolves the flag
+// by the wrapped resolver.
+func (r *fileOverrideResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *fileOverrideResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	visitorCode, hasVisitor := getTargetingKey(evalContext)
+	entry, ok := r.file.lookup(flag, visitorCode)
+	if !ok {
+		return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+	}
+	if hasVisitor {
+		if err := r.client.AddData(visitorCode, ToKameleoon(evalContext)...); err != nil {
+			return sdkFailure(defaultValue, err, "", nil)
+		}
+	}
+	metadata := openfeature.FlagMetadata{OverrideMetadataSource: overrideSourceFile}
+	variables := entry.Variables
+	if variables == nil {
+		var err error
+		if variables, err = r.client.GetFeatureVariationVariables(flag, entry.Variation); err != nil {
+			return sdkFailure(defaultValue, err, entry.Variation, metadata)
+		}
+	}
+	res := resolveVariable(ctx, evalContext, variables, entry.Variation, defaultValue)
+	res.metadata = metadata
+	if res.err == nil {
+		res.reason = OverrideReason
+	}
+	return res
+}
@@ -0,0 +1,151 @@
+package kameleoon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOverrideFile = `
+flags:
+  recommendations:
+    variation: treatment
+    visitors:
+      qaVisitor:
+        variation: control
+  banner:
+    variation: on
+    variables:
+      title: Local title
+`
+
+func newOverrideFileTestProvider(t *testing.T, path string) *kameleoonProvider {
+	client := kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "recommendations",
+		DefaultVariation: "control",
+		Variations: []kameleoontest.Variation{
+			{Key: "control", Variables: map[string]interface{}{"count": 5}},
+			{Key: "treatment", Variables: map[string]interface{}{"count": 10}},
+		},
+	})
+	options := newProviderOptions([]Option{WithOverrideFile(path)})
+	options.overrideFilePollInterval = 10 * time.Millisecond
+	provider := newProvider("siteCode", client, options)
+	t.Cleanup(provider.Shutdown)
+	return provider
+}
+
+func TestParseOverrideFile_AcceptsYamlAndJson(t *testing.T) {
+	// Act
+	yamlFlags, yamlErr := parseOverrideFile([]byte(testOverrideFile))
+	jsonFlags, jsonErr := parseOverrideFile(
+		[]byte(`{"flags": {"banner": {"variation": "on", "variables": {"count": 3}}}}`))
+	_, invalidErr := parseOverrideFile([]byte(`flags: [`))
+	_, noVariationErr := parseOverrideFile([]byte(`{"flags": {"banner": {"variables": {"count": 3}}}}`))
+	_, noVisitorVariationErr := parseOverrideFile([]byte(`{"flags": {"banner": {"visitors": {"qaVisitor": {}}}}}`))
+
+	// Assert
+	assert.NoError(t, yamlErr)
+	assert.Equal(t, "control", yamlFlags["recommendations"].Visitors["qaVisitor"].Variation)
+	assert.Equal(t, map[string]interface{}{"title": "Local title"}, yamlFlags["banner"].Variables)
+	assert.NoError(t, jsonErr)
+	assert.Equal(t, map[string]interface{}{"count": 3.0}, jsonFlags["banner"].Variables)
+	assert.Error(t, invalidErr)
+	assert.Error(t, noVariationErr)
+	assert.Error(t, noVisitorVariationErr)
+}
+
+func TestKameleoonProvider_OverrideFile_AddsVisitorData(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testOverrideFile), 0o600))
+	client := kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "recommendations",
+		DefaultVariation: "control",
+		Variations: []kameleoontest.Variation{
+			{Key: "control", Variables: map[string]interface{}{"count": 5}},
+			{Key: "treatment", Variables: map[string]interface{}{"count": 10}},
+		},
+	})
+	options := newProviderOptions([]Option{WithOverrideFile(path)})
+	provider := newProvider("siteCode", client, options)
+	defer provider.Shutdown()
+
+	// Act
+	result := provider.FloatEvaluation(context.Background(), "recommendations", 0, openfeature.FlattenedContext{
+		"targetingKey": "visitor",
+		Data.Type.CustomData: map[string]interface{}{
+			Data.CustomDataType.Index:  1,
+			Data.CustomDataType.Values: "premium",
+		},
+	})
+
+	// Assert
+	assert.Equal(t, OverrideReason, result.Reason)
+	assert.Len(t, client.VisitorData("visitor"), 1)
+}
+
+func TestKameleoonProvider_OverrideFile_TakesPrecedenceOverClient(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testOverrideFile), 0o600))
+	provider := newOverrideFileTestProvider(t, path)
+
+	// Act
+	count := provider.FloatEvaluation(context.Background(), "recommendations", 0,
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+	qaCount := provider.FloatEvaluation(context.Background(), "recommendations", 0,
+		openfeature.FlattenedContext{"targetingKey": "qaVisitor"})
+	title := provider.StringEvaluation(context.Background(), "banner", "", openfeature.FlattenedContext{})
+
+	// Assert
+	assert.Equal(t, 10.0, count.Value)
+	assert.Equal(t, OverrideReason, count.Reason)
+	assert.Equal(t, "file", count.FlagMetadata[OverrideMetadataSource])
+	assert.Equal(t, 5.0, qaCount.Value)
+	assert.Equal(t, "control", qaCount.Variant)
+	assert.Equal(t, "Local title", title.Value)
+}
+
+func TestKameleoonProvider_OverrideFile_ReloadsOnChange(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	provider := newOverrideFileTestProvider(t, path)
+	evalContext := openfeature.FlattenedContext{"targetingKey": "visitor"}
+
+	// Act
+	before := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+	require.NoError(t, os.WriteFile(path,
+		[]byte(`{"flags": {"recommendations": {"variation": "treatment"}}}`), 0o600))
+	var event openfeature.Event
+	select {
+	case event = <-provider.EventChannel():
+	case <-time.After(time.Second):
+	}
+	after := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+
+	// Assert
+	assert.Equal(t, 5.0, before.Value)
+	assert.Equal(t, openfeature.ProviderConfigChange, event.EventType)
+	assert.Equal(t, 10.0, after.Value)
+}
+
+func TestNewProviderOptions_ReadsOverrideFileFromEnvironment(t *testing.T) {
+	// Arrange
+	t.Setenv(OverrideFileEnv, "/etc/kameleoon/overrides.yaml")
+
+	// Act
+	fromEnv := newProviderOptions(nil)
+	fromOption := newProviderOptions([]Option{WithOverrideFile("overrides.yaml")})
+
+	// Assert
+	assert.Equal(t, "/etc/kameleoon/overrides.yaml", fromEnv.overrideFilePath)
+	assert.Equal(t, "overrides.yaml", fromOption.overrideFilePath)
+}
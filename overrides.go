@@ -0,0 +1,207 @@
+package kameleoon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// OverrideReason is the reason of resolutions served from a variation override.
+const OverrideReason openfeature.Reason = "OVERRIDE"
+
+// OverrideContextKey is the reserved context key forcing variations for the evaluation if the provider is
+// created with WithContextOverrides. Its value maps feature keys to variation keys,
+// e.g. map[string]interface{}{"banner": "on"}. It takes precedence over the overrides set by SetOverride.
+const OverrideContextKey = "kameleoonOverrides"
+
+// WithContextOverrides makes the provider serve the variations forced by OverrideContextKey.
+//
+// Anyone who controls the evaluation context, e.g. through request parameters copied into it, can then force
+// any variation, so only enable it in environments where the context comes from trusted sources, such as QA.
+// Without this option OverrideContextKey is ignored.
+func WithContextOverrides() Option {
+	return func(o *providerOptions) {
+		o.contextOverrides = true
+	}
+}
+
+// Flag metadata keys reported for resolutions served from a variation override.
+const (
+	// OverrideMetadataSource is "api" for overrides set by SetOverride and "context" for OverrideContextKey.
+	OverrideMetadataSource = "overrideSource"
+	// OverrideMetadataExpiresAt is the expiry of the override in RFC 3339 format, if any.
+	OverrideMetadataExpiresAt = "overrideExpiresAt"
+)
+
+const (
+	overrideSourceAPI     = "api"
+	overrideSourceContext = "context"
+)
+
+// overrideKey identifies the override of a feature flag for a visitor.
+type overrideKey struct {
+	visitorCode string
+	flag        string
+}
+
+// override is a forced variation, expiresAt is zero if it never expires.
+type override struct {
+	variation string
+	expiresAt time.Time
+}
+
+// overrideRegistry holds the variation overrides set through the provider API.
+type overrideRegistry struct {
+	mu      sync.Mutex
+	entries map[overrideKey]override
+	now     func() time.Time
+}
+
+// newOverrideRegistry creates a new instance of overrideRegistry.
+func newOverrideRegistry() *overrideRegistry {
+	return &overrideRegistry{
+		entries: make(map[overrideKey]override),
+		now:     time.Now,
+	}
+}
+
+func (r *overrideRegistry) set(visitorCode, flag, variation string, ttl time.Duration) {
+	entry := override{variation: variation}
+	if ttl > 0 {
+		entry.expiresAt = r.now().Add(ttl)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[overrideKey{visitorCode: visitorCode, flag: flag}] = entry
+}
+
+// get returns the override of the flag for the visitor, an expired override is removed.
+func (r *overrideRegistry) get(visitorCode, flag string) (override, bool) {
+	key := overrideKey{visitorCode: visitorCode, flag: flag}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[key]
+	if !ok {
+		return entry, false
+	}
+	if !entry.expiresAt.IsZero() && !r.now().Before(entry.expiresAt) {
+		delete(r.entries, key)
+		return entry, false
+	}
+	return entry, true
+}
+
+func (r *overrideRegistry) remove(visitorCode, flag string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, overrideKey{visitorCode: visitorCode, flag: flag})
+}
+
+func (r *overrideRegistry) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = make(map[overrideKey]override)
+}
+
+// SetOverride forces the variation of the feature flag for the visitor. The override expires after ttl,
+// or never if ttl isn't positive. Resolutions served from it have the OVERRIDE reason.
+func (p *kameleoonProvider) SetOverride(visitorCode string, flag string, variation string, ttl time.Duration) {
+	p.overrides.set(visitorCode, flag, variation, ttl)
+}
+
+// RemoveOverride removes the override of the feature flag for the visitor.
+func (p *kameleoonProvider) RemoveOverride(visitorCode string, flag string) {
+	p.overrides.remove(visitorCode, flag)
+}
+
+// ClearOverrides removes all overrides set by SetOverride.
+func (p *kameleoonProvider) ClearOverrides() {
+	p.overrides.clear()
+}
+
+// overrideResolver serves forced variations before the wrapped resolver calculates one.
+type overrideResolver struct {
+	client           kameleoon.KameleoonClient
+	overrides        *overrideRegistry
+	contextOverrides bool
+	resolver         resolver
+}
+
+// newOverrideResolver creates a new instance of overrideResolver, OverrideContextKey is read only
+// if contextOverrides is true.
+func newOverrideResolver(
+	client kameleoon.KameleoonClient, overrides *overrideRegistry, contextOverrides bool, resolver resolver,
+) *overrideResolver {
+	return &overrideResolver{
+		client:           client,
+		overrides:        overrides,
+		contextOverrides: contextOverrides,
+		resolver:         resolver,
+	}
+}
+
+// Resolve returns the variables of the forced variation if there is an override for the visitor and flag,
+// otherwise resolves the flag by the wrapped resolver.
+func (r *overrideResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *overrideResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	visitorCode, ok := getTargetingKey(evalContext)
+	if !ok {
+		return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+	}
+	metadata := openfeature.FlagMetadata{}
+	variation, ok := r.contextOverride(evalContext, flag)
+	if ok {
+		metadata[OverrideMetadataSource] = overrideSourceContext
+	} else {
+		entry, found := r.overrides.get(visitorCode, flag)
+		if !found {
+			return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+		}
+		variation = entry.variation
+		metadata[OverrideMetadataSource] = overrideSourceAPI
+		if !entry.expiresAt.IsZero() {
+			metadata[OverrideMetadataExpiresAt] = entry.expiresAt.Format(time.RFC3339)
+		}
+	}
+
+	if err := r.client.AddData(visitorCode, ToKameleoon(evalContext)...); err != nil {
+		return sdkFailure(defaultValue, err, "", nil)
+	}
+	variables, err := r.client.GetFeatureVariationVariables(flag, variation)
+	if err != nil {
+		return sdkFailure(defaultValue, err, variation, metadata)
+	}
+	res := resolveVariable(ctx, evalContext, variables, variation, defaultValue)
+	res.metadata = metadata
+	if res.err == nil {
+		res.reason = OverrideReason
+	}
+	return res
+}
+
+// contextOverride returns the variation forced for the flag by OverrideContextKey, if context overrides
+// are enabled.
+func (r *overrideResolver) contextOverride(evalContext openfeature.FlattenedContext, flag string) (string, bool) {
+	if !r.contextOverrides {
+		return "", false
+	}
+	var variation interface{}
+	switch overrides := evalContext[OverrideContextKey].(type) {
+	case map[string]interface{}:
+		variation = overrides[flag]
+	case map[string]string:
+		variation = overrides[flag]
+	}
+	value, ok := variation.(string)
+	return value, ok && value != ""
+}
@@ -0,0 +1,117 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOverrideTestProvider(opts ...Option) *kameleoonProvider {
+	client := kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "recommendations",
+		DefaultVariation: "control",
+		Variations: []kameleoontest.Variation{
+			{Key: "control", Variables: map[string]interface{}{"count": 5}},
+			{Key: "treatment", Variables: map[string]interface{}{"count": 10}},
+		},
+	})
+	return NewKameleoonProviderWithClient("siteCode", client, opts...)
+}
+
+func TestOverrideRegistry_Expiry(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	registry := newOverrideRegistry()
+	registry.now = func() time.Time { return now }
+	registry.set("visitor", "flag", "on", time.Minute)
+	registry.set("visitor", "permanent", "on", 0)
+
+	// Act
+	_, beforeExpiry := registry.get("visitor", "flag")
+	now = now.Add(time.Minute)
+	_, afterExpiry := registry.get("visitor", "flag")
+	_, permanent := registry.get("visitor", "permanent")
+
+	// Assert
+	assert.True(t, beforeExpiry)
+	assert.False(t, afterExpiry)
+	assert.True(t, permanent)
+}
+
+func TestKameleoonProvider_SetOverride_ServesForcedVariation(t *testing.T) {
+	// Arrange
+	provider := newOverrideTestProvider()
+	provider.SetOverride("qaVisitor", "recommendations", "treatment", time.Hour)
+
+	// Act
+	forced := provider.FloatEvaluation(context.Background(), "recommendations", 0,
+		openfeature.FlattenedContext{"targetingKey": "qaVisitor"})
+	regular := provider.FloatEvaluation(context.Background(), "recommendations", 0,
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+	provider.RemoveOverride("qaVisitor", "recommendations")
+	removed := provider.FloatEvaluation(context.Background(), "recommendations", 0,
+		openfeature.FlattenedContext{"targetingKey": "qaVisitor"})
+
+	// Assert
+	assert.Equal(t, 10.0, forced.Value)
+	assert.Equal(t, "treatment", forced.Variant)
+	assert.Equal(t, OverrideReason, forced.Reason)
+	assert.Equal(t, "api", forced.FlagMetadata[OverrideMetadataSource])
+	assert.NotEmpty(t, forced.FlagMetadata[OverrideMetadataExpiresAt])
+	assert.Equal(t, 5.0, regular.Value)
+	assert.NotEqual(t, OverrideReason, regular.Reason)
+	assert.Equal(t, 5.0, removed.Value)
+}
+
+func TestKameleoonProvider_ContextOverride_TakesPrecedence(t *testing.T) {
+	// Arrange
+	provider := newOverrideTestProvider(WithContextOverrides())
+	provider.SetOverride("qaVisitor", "recommendations", "treatment", 0)
+	evalContext := openfeature.FlattenedContext{
+		"targetingKey":     "qaVisitor",
+		OverrideContextKey: map[string]interface{}{"recommendations": "control"},
+	}
+
+	// Act
+	result := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+
+	// Assert
+	assert.Equal(t, 5.0, result.Value)
+	assert.Equal(t, OverrideReason, result.Reason)
+	assert.Equal(t, "context", result.FlagMetadata[OverrideMetadataSource])
+}
+
+func TestKameleoonProvider_ContextOverride_IgnoredWithoutOption(t *testing.T) {
+	// Arrange
+	provider := newOverrideTestProvider()
+	evalContext := openfeature.FlattenedContext{
+		"targetingKey":     "visitor",
+		OverrideContextKey: map[string]interface{}{"recommendations": "treatment"},
+	}
+
+	// Act
+	result := provider.FloatEvaluation(context.Background(), "recommendations", 0, evalContext)
+
+	// Assert
+	assert.Equal(t, 5.0, result.Value)
+	assert.Equal(t, "control", result.Variant)
+	assert.NotEqual(t, OverrideReason, result.Reason)
+}
+
+func TestKameleoonProvider_OverrideWithUnknownVariation_ReturnsFlagNotFound(t *testing.T) {
+	// Arrange
+	provider := newOverrideTestProvider()
+	provider.SetOverride("qaVisitor", "recommendations", "unknown", 0)
+
+	// Act
+	result := provider.FloatEvaluation(context.Background(), "recommendations", 0,
+		openfeature.FlattenedContext{"targetingKey": "qaVisitor"})
+
+	// Assert
+	assert.Equal(t, 0.0, result.Value)
+	assert.Equal(t, openfeature.FlagNotFoundCode, result.ResolutionDetail().ErrorCode)
+}
@@ -0,0 +1,65 @@
+package kameleoon
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// PeekContextKey is the reserved context key of evaluations in peek mode, a bool or a "true"/"false" string.
+// Evaluations in peek mode neither add data nor track exposures, e.g. for admin tools, previews and batch jobs.
+const PeekContextKey = "kameleoonPeek"
+
+// peekContextKey is the context.Context key of the peek mode.
+type peekContextKey struct{}
+
+// WithPeek returns a copy of ctx whose evaluations are in peek mode: they neither add data nor track exposures.
+// An evaluation in peek mode costs more than a tracked one: besides the lookup of the variables, it looks up
+// the "off" variation and calls GetActiveFeatures, which calculates the variations of all flags of the visitor.
+// Evaluating many flags of a visitor in peek mode costs one GetActiveFeatures call per flag.
+func WithPeek(ctx context.Context) context.Context {
+	return context.WithValue(ctx, peekContextKey{}, true)
+}
+
+// isPeek reports whether the evaluation is in peek mode, by the evaluation context or by ctx.
+func isPeek(ctx context.Context, evalContext openfeature.FlattenedContext) bool {
+	switch peek := evalContext[PeekContextKey].(type) {
+	case bool:
+		return peek
+	case string:
+		return peek == "true"
+	}
+	if ctx == nil {
+		return false
+	}
+	peek, _ := ctx.Value(peekContextKey{}).(bool)
+	return peek
+}
+
+// peekResolver resolves evaluations in peek mode as untracked evaluations without data.
+type peekResolver struct {
+	resolver resolver
+}
+
+// newPeekResolver creates a new instance of peekResolver.
+func newPeekResolver(resolver resolver) *peekResolver {
+	return &peekResolver{
+		resolver: resolver,
+	}
+}
+
+// Resolve resolves the flag by the wrapped resolver, without data nor tracking in peek mode.
+func (r *peekResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *peekResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	if isPeek(ctx, evalContext) {
+		ctx, evalContext = withoutTracking(ctx, evalContext)
+	}
+	return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+}
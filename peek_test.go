@@ -0,0 +1,165 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newPeekTestClient creates a client mock which fails on calls of AddData with data.
+func newPeekTestClient() *MockKameleoonClient {
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything)
+	clientMock.On("AddData", "visitor", []types.Data(nil)).Return(nil)
+	clientMock.On("GetActiveFeatures", "visitor").Return(map[string]types.Variation{
+		"banner": {Key: "on"},
+	}, nil)
+	clientMock.On("GetFeatureVariationVariables", "banner", "off").Return(map[string]interface{}{}, nil)
+	clientMock.On("GetFeatureVariationVariables", "banner", "on").
+		Return(map[string]interface{}{"enabled": true}, nil)
+	return clientMock
+}
+
+func TestIsPeek_ReadsContextKeyThenContextValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		evalContext openfeature.FlattenedContext
+		peek        bool
+	}{
+		{"bool", context.Background(), openfeature.FlattenedContext{PeekContextKey: true}, true},
+		{"string", context.Background(), openfeature.FlattenedContext{PeekContextKey: "true"}, true},
+		{"context value", WithPeek(context.Background()), openfeature.FlattenedContext{}, true},
+		{"context key first", WithPeek(context.Background()), openfeature.FlattenedContext{PeekContextKey: false}, false},
+		{"missing", context.Background(), openfeature.FlattenedContext{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			peek := isPeek(tt.ctx, tt.evalContext)
+
+			// Assert
+			assert.Equal(t, tt.peek, peek)
+		})
+	}
+}
+
+func TestKameleoonProvider_Peek_DoesNotAddDataOrTrackExposure(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		evalContext openfeature.FlattenedContext
+	}{
+		{"context key", context.Background(), openfeature.FlattenedContext{PeekContextKey: true}},
+		{"context value", WithPeek(context.Background()), openfeature.FlattenedContext{}},
+		{"transaction", WithPeek(WithTransaction(context.Background(), openfeature.NewEvaluationContext(
+			"visitor", map[string]interface{}{Data.Type.Device: "phone"}))), openfeature.FlattenedContext{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			clientMock := newPeekTestClient()
+			provider := NewKameleoonProviderWithClient("siteCode", clientMock)
+			defer provider.Shutdown()
+			evalContext := openfeature.FlattenedContext{
+				"targetingKey":       "visitor",
+				Data.Type.Conversion: map[string]interface{}{Data.ConversionType.GoalId: 1},
+			}
+			for key, value := range tt.evalContext {
+				evalContext[key] = value
+			}
+
+			// Act
+			result := provider.BooleanEvaluation(tt.ctx, "banner", false, evalContext)
+
+			// Assert
+			assert.True(t, result.Value)
+			assert.Equal(t, "on", result.Variant)
+			assert.True(t, provider.pending.empty())
+			clientMock.AssertNotCalled(t, "GetFeatureVariationKey", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestKameleoonProvider_UntrackedAttribute_DoesNotDisableTracking(t *testing.T) {
+	// Arrange
+	client := kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "banner",
+		DefaultVariation: "on",
+		Variations:       []kameleoontest.Variation{{Key: "on", Variables: map[string]interface{}{"enabled": true}}},
+	})
+	provider := NewKameleoonProviderWithClient("siteCode", client)
+	defer provider.Shutdown()
+
+	// Act
+	result := provider.BooleanEvaluation(context.Background(), "banner", false,
+		openfeature.FlattenedContext{"targetingKey": "visitor", "kameleoonUntracked": true})
+
+	// Assert
+	assert.True(t, result.Value)
+	assert.Equal(t, []kameleoontest.Exposure{{VisitorCode: "visitor", FeatureKey: "banner", VariationKey: "on"}},
+		client.Exposures())
+}
+
+func TestKameleoonProvider_Snapshot_Peek_DoesNotAddData(t *testing.T) {
+	// Arrange
+	clientMock := newPeekTestClient()
+	clientMock.On("GetFeatureList").Return([]string{"banner"})
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock)
+	defer provider.Shutdown()
+
+	// Act
+	ctx, err := provider.Snapshot(WithPeek(context.Background()), openfeature.NewEvaluationContext("visitor",
+		map[string]interface{}{Data.Type.Conversion: map[string]interface{}{Data.ConversionType.GoalId: 1}}))
+	require.NoError(t, err)
+	result := provider.BooleanEvaluation(ctx, "banner", false, openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+	// Assert
+	assert.True(t, result.Value)
+	assert.True(t, provider.pending.empty())
+}
+
+func TestKameleoonProvider_Peek_ResolvesSingleFlagState(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		reason  openfeature.Reason
+		code    openfeature.ErrorCode
+		variant string
+	}{
+		{"active", "banner", openfeature.TargetingMatchReason, "", "on"},
+		{"inactive", "layout", openfeature.DefaultReason, "", "off"},
+		{"disabled", "legacy", openfeature.DisabledReason, "", "off"},
+		{"unknown", "unknown", openfeature.ErrorReason, openfeature.FlagNotFoundCode, "off"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			client := kameleoontest.NewClient(
+				kameleoontest.Feature{Key: "banner", DefaultVariation: "on",
+					Variations: []kameleoontest.Variation{{Key: "on"}}},
+				kameleoontest.Feature{Key: "layout", DefaultVariation: "off",
+					Variations: []kameleoontest.Variation{{Key: "off"}}},
+				kameleoontest.Feature{Key: "legacy", EnvironmentDisabled: true},
+			)
+			provider := NewKameleoonProviderWithClient("siteCode", client)
+			defer provider.Shutdown()
+
+			// Act
+			result := provider.BooleanEvaluation(WithPeek(context.Background()), tt.flag, false,
+				openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+			// Assert
+			assert.Equal(t, tt.reason, result.Reason)
+			assert.Equal(t, tt.code, result.ResolutionDetail().ErrorCode)
+			assert.Equal(t, tt.variant, result.Variant)
+			assert.Empty(t, client.Exposures())
+		})
+	}
+}
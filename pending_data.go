@@ -0,0 +1,127 @@
+package kameleoon
+
+import (
+	"sort"
+	"sync"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/types"
+)
+
+// pendingVisitors records the visitors which may have data not sent to Kameleoon yet.
+type pendingVisitors struct {
+	mu    sync.Mutex
+	codes map[string]struct{}
+}
+
+// newPendingVisitors creates a new instance of pendingVisitors.
+func newPendingVisitors() *pendingVisitors {
+	return &pendingVisitors{
+		codes: make(map[string]struct{}),
+	}
+}
+
+func (v *pendingVisitors) add(visitorCode string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.codes[visitorCode] = struct{}{}
+}
+
+func (v *pendingVisitors) remove(visitorCode string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.codes, visitorCode)
+}
+
+// empty reports whether no visitor is recorded.
+func (v *pendingVisitors) empty() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.codes) == 0
+}
+
+// drain returns the recorded visitor codes in order and forgets them.
+func (v *pendingVisitors) drain() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	codes := make([]string, 0, len(v.codes))
+	for code := range v.codes {
+		codes = append(codes, code)
+	}
+	v.codes = make(map[string]struct{})
+	sort.Strings(codes)
+	return codes
+}
+
+// trackingClient is the KameleoonClient used by the resolvers. It records the visitors whose data is added
+// or whose variations are assigned through the provider, since the client sends this data on its next flush.
+type trackingClient struct {
+	kameleoon.KameleoonClient
+	pending *pendingVisitors
+}
+
+// newTrackingClient creates a new instance of trackingClient.
+func newTrackingClient(client kameleoon.KameleoonClient, pending *pendingVisitors) *trackingClient {
+	return &trackingClient{
+		KameleoonClient: client,
+		pending:         pending,
+	}
+}
+
+// track records the visitor if the call succeeded.
+func (c *trackingClient) track(visitorCode string, err error) {
+	if err == nil {
+		c.pending.add(visitorCode)
+	}
+}
+
+func (c *trackingClient) AddData(visitorCode string, allData ...types.Data) error {
+	err := c.KameleoonClient.AddData(visitorCode, allData...)
+	if len(allData) > 0 {
+		c.track(visitorCode, err)
+	}
+	return err
+}
+
+func (c *trackingClient) TrackConversion(visitorCode string, goalID int, isUniqueIdentifier ...bool) error {
+	err := c.KameleoonClient.TrackConversion(visitorCode, goalID, isUniqueIdentifier...)
+	c.track(visitorCode, err)
+	return err
+}
+
+func (c *trackingClient) TrackConversionRevenue(
+	visitorCode string, goalID int, revenue float64, isUniqueIdentifier ...bool,
+) error {
+	err := c.KameleoonClient.TrackConversionRevenue(visitorCode, goalID, revenue, isUniqueIdentifier...)
+	c.track(visitorCode, err)
+	return err
+}
+
+func (c *trackingClient) GetFeatureVariationKey(
+	visitorCode string, featureKey string, isUniqueIdentifier ...bool,
+) (string, error) {
+	variationKey, err := c.KameleoonClient.GetFeatureVariationKey(visitorCode, featureKey, isUniqueIdentifier...)
+	c.track(visitorCode, err)
+	return variationKey, err
+}
+
+func (c *trackingClient) FlushVisitor(visitorCode string, isUniqueIdentifier ...bool) error {
+	err := c.KameleoonClient.FlushVisitor(visitorCode, isUniqueIdentifier...)
+	if err == nil {
+		c.pending.remove(visitorCode)
+	}
+	return err
+}
+
+func (c *trackingClient) FlushVisitorInstantly(visitorCode string) error {
+	err := c.KameleoonClient.FlushVisitorInstantly(visitorCode)
+	if err == nil {
+		c.pending.remove(visitorCode)
+	}
+	return err
+}
+
+func (c *trackingClient) FlushAll(instant ...bool) {
+	c.pending.drain()
+	c.KameleoonClient.FlushAll(instant...)
+}
@@ -0,0 +1,73 @@
+package kameleoon
+
+import (
+	"context"
+	"sync/atomic"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// clientReadiness reports whether the Kameleoon client of a provider has loaded a configuration.
+//
+// The Kameleoon client only reports its readiness through the blocking WaitInit, and it returns FeatureNotFound
+// for every flag until the configuration is loaded. The client is therefore considered ready once Init succeeds,
+// the client notifies a configuration update or it has feature flags.
+type clientReadiness struct {
+	client kameleoon.KameleoonClient
+	ready  int32
+}
+
+// newClientReadiness creates a new instance of clientReadiness for the client, ready tells whether the client
+// is ready from the start, e.g. a client serving a local configuration.
+func newClientReadiness(client kameleoon.KameleoonClient, ready bool) *clientReadiness {
+	cr := &clientReadiness{client: client}
+	if ready {
+		cr.ready = 1
+	}
+	return cr
+}
+
+// isReady reports whether the client has loaded a configuration.
+func (cr *clientReadiness) isReady() bool {
+	if atomic.LoadInt32(&cr.ready) == 1 {
+		return true
+	}
+	if len(cr.client.GetFeatureList()) == 0 {
+		return false
+	}
+	cr.setReady()
+	return true
+}
+
+// setReady records that the client has loaded a configuration.
+func (cr *clientReadiness) setReady() {
+	atomic.StoreInt32(&cr.ready, 1)
+}
+
+// readinessResolver fails evaluations with PROVIDER_NOT_READY until the client has loaded a configuration,
+// instead of FLAG_NOT_FOUND returned by the Kameleoon client for every flag.
+type readinessResolver struct {
+	resolver resolver
+	ready    func() bool
+}
+
+// newReadinessResolver creates a new instance of readinessResolver reporting the readiness by the given function.
+func newReadinessResolver(r resolver, ready func() bool) *readinessResolver {
+	return &readinessResolver{resolver: r, ready: ready}
+}
+
+func (r *readinessResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *readinessResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	if !r.ready() {
+		return sdkFailure(defaultValue, ErrLiveClientNotReady, "", nil)
+	}
+	return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+}
@@ -0,0 +1,59 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKameleoonProvider_EvaluationBeforeClientInitialized_FailsWithProviderNotReady(t *testing.T) {
+	// Arrange
+	visitorCode := "visitor"
+	initDone := make(chan time.Time)
+	liveMock := new(MockKameleoonClient)
+	liveMock.On("OnUpdateConfiguration", mock.Anything)
+	liveMock.On("WaitInit").WaitUntil(initDone).Return(nil)
+	liveMock.On("GetFeatureList").Return([]string(nil))
+	liveMock.On("AddData", visitorCode, mock.Anything).Return(nil)
+	liveMock.On("FlushAll", []bool{true})
+	liveMock.On("GetFeatureVariationKey", visitorCode, "banner", []bool(nil)).Return("on", nil)
+	liveMock.On("GetFeatureVariationVariables", "banner", "on").Return(map[string]interface{}{}, nil)
+	provider, err := newLiveProvider("siteCode", liveMock, "", newProviderOptions(nil))
+	require.NoError(t, err)
+	defer provider.Shutdown()
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+
+	// Act
+	initErr := make(chan error)
+	go func() { initErr <- provider.Init(openfeature.EvaluationContext{}) }()
+	before := provider.BooleanEvaluation(context.Background(), "banner", false, evalContext)
+	close(initDone)
+	require.NoError(t, <-initErr)
+	after := provider.BooleanEvaluation(context.Background(), "banner", false, evalContext)
+
+	// Assert
+	assert.Equal(t, openfeature.ProviderNotReadyCode, before.ResolutionDetail().ErrorCode)
+	assert.False(t, before.Value)
+	assert.Equal(t, string(openfeature.ProviderNotReadyCode), before.FlagMetadata[ErrorCodeMetadataKey])
+	liveMock.AssertNumberOfCalls(t, "GetFeatureVariationKey", 1)
+	assert.NoError(t, after.Error())
+	assert.True(t, after.Value)
+}
+
+func TestKameleoonProvider_ClientWithoutReadiness_IsReady(t *testing.T) {
+	// Arrange
+	provider := NewKameleoonProviderWithClient("siteCode", newTransactionTestClient())
+	defer provider.Shutdown()
+
+	// Act
+	result := provider.BooleanEvaluation(context.Background(), "banner", false,
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+	// Assert
+	assert.NoError(t, result.Error())
+}
@@ -0,0 +1,154 @@
+package kameleoon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/logging"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// DefaultRemoteDataTTL is how long the remote data of a visitor stays valid if RemoteDataConfig.TTL isn't set.
+const DefaultRemoteDataTTL = 30 * time.Minute
+
+// RemoteDataConfig is used to configure the prefetch of remote visitor data.
+type RemoteDataConfig struct {
+	// Filter selects the remote visitor data to fetch, types.DefaultRemoteVisitorDataFilter() if it's zero.
+	Filter types.RemoteVisitorDataFilter
+	// Timeout of a fetch, the default timeout of the Kameleoon SDK if it isn't set.
+	Timeout time.Duration
+	// Background fetches the remote data without waiting for it, so the first evaluations of a visitor
+	// may not use it yet.
+	Background bool
+	// TTL is how long the remote data of a visitor stays valid before it's fetched again.
+	TTL time.Duration
+}
+
+// WithRemoteVisitorData fetches the remote visitor data of a visitor, such as previous visits, the first time
+// the visitor is evaluated and once its TTL expires. The data is added to the visitor, so flags targeting
+// segments based on it evaluate correctly. A fetch failure is logged and the evaluation goes on without the
+// remote data, the fetch is retried once the TTL expires.
+// Untracked evaluations, in peek mode or for visitors without the legal consent, don't fetch the remote data.
+func WithRemoteVisitorData(config RemoteDataConfig) Option {
+	return func(o *providerOptions) {
+		o.remoteDataConfig = &config
+	}
+}
+
+// remoteFetch is the fetch of the remote data of a visitor.
+type remoteFetch struct {
+	done      chan struct{}
+	expiresAt time.Time
+}
+
+// remoteDataLoader fetches the remote visitor data at most once per visitor and TTL.
+type remoteDataLoader struct {
+	client kameleoon.KameleoonClient
+	config RemoteDataConfig
+
+	mu        sync.Mutex
+	fetches   map[string]*remoteFetch
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// newRemoteDataLoader creates a new instance of remoteDataLoader, applying defaults for unset values.
+func newRemoteDataLoader(client kameleoon.KameleoonClient, config RemoteDataConfig) *remoteDataLoader {
+	if config.Filter == (types.RemoteVisitorDataFilter{}) {
+		config.Filter = types.DefaultRemoteVisitorDataFilter()
+	}
+	if config.TTL <= 0 {
+		config.TTL = DefaultRemoteDataTTL
+	}
+	return &remoteDataLoader{
+		client:  client,
+		config:  config,
+		fetches: make(map[string]*remoteFetch),
+		now:     time.Now,
+	}
+}
+
+// load fetches the remote data of the visitor unless it's valid. It waits for the fetch, or for the one
+// in progress, unless the loader fetches in the background.
+func (l *remoteDataLoader) load(visitorCode string) {
+	l.mu.Lock()
+	now := l.now()
+	fetch, ok := l.fetches[visitorCode]
+	if ok && (fetch.expiresAt.IsZero() || now.Before(fetch.expiresAt)) {
+		l.mu.Unlock()
+		if !l.config.Background {
+			<-fetch.done
+		}
+		return
+	}
+	l.sweep(now)
+	fetch = &remoteFetch{done: make(chan struct{})}
+	l.fetches[visitorCode] = fetch
+	l.mu.Unlock()
+
+	if l.config.Background {
+		go l.fetch(visitorCode, fetch)
+	} else {
+		l.fetch(visitorCode, fetch)
+	}
+}
+
+// fetch fetches the remote data of the visitor and adds it to the visitor.
+func (l *remoteDataLoader) fetch(visitorCode string, fetch *remoteFetch) {
+	_, err := l.client.GetRemoteVisitorDataWithFilter(visitorCode, true, l.config.Filter,
+		kameleoon.RemoteVisitorDataOptParams{Timeout: l.config.Timeout})
+	if err != nil {
+		logging.Warning("Failed to fetch the remote data of the visitor %s: %s", visitorCode, err)
+	}
+	l.mu.Lock()
+	fetch.expiresAt = l.now().Add(l.config.TTL)
+	l.mu.Unlock()
+	close(fetch.done)
+}
+
+// sweep forgets the expired fetches at most once per TTL, the lock must be held.
+func (l *remoteDataLoader) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.config.TTL {
+		return
+	}
+	l.lastSweep = now
+	for visitorCode, fetch := range l.fetches {
+		if !fetch.expiresAt.IsZero() && !now.Before(fetch.expiresAt) {
+			delete(l.fetches, visitorCode)
+		}
+	}
+}
+
+// remoteDataResolver loads the remote data of the visitor before the evaluation.
+type remoteDataResolver struct {
+	loader   *remoteDataLoader
+	resolver resolver
+}
+
+// newRemoteDataResolver creates a new instance of remoteDataResolver.
+func newRemoteDataResolver(loader *remoteDataLoader, resolver resolver) *remoteDataResolver {
+	return &remoteDataResolver{
+		loader:   loader,
+		resolver: resolver,
+	}
+}
+
+// Resolve loads the remote data of the visitor, unless the evaluation is untracked since the remote data is added
+// to the visitor, then resolves the flag by the wrapped resolver.
+func (r *remoteDataResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *remoteDataResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	if visitorCode, ok := getTargetingKey(evalContext); ok && !isUntracked(ctx) {
+		r.loader.load(visitorCode)
+	}
+	return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+}
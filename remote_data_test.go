@@ -0,0 +1,156 @@
+package kameleoon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewRemoteDataLoader_AppliesDefaults(t *testing.T) {
+	// Act
+	loader := newRemoteDataLoader(new(MockKameleoonClient), RemoteDataConfig{})
+
+	// Assert
+	assert.Equal(t, types.DefaultRemoteVisitorDataFilter(), loader.config.Filter)
+	assert.Equal(t, DefaultRemoteDataTTL, loader.config.TTL)
+}
+
+func TestRemoteDataLoader_FetchesOncePerTTL(t *testing.T) {
+	// Arrange
+	filter := types.RemoteVisitorDataFilter{PreviousVisitAmount: 5, CustomData: true}
+	params := []kameleoon.RemoteVisitorDataOptParams{{Timeout: time.Second}}
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("GetRemoteVisitorDataWithFilter", "visitor", true, filter, params).Return([]types.Data{}, nil)
+	now := time.Now()
+	loader := newRemoteDataLoader(clientMock, RemoteDataConfig{Filter: filter, Timeout: time.Second, TTL: time.Minute})
+	loader.now = func() time.Time { return now }
+
+	// Act
+	loader.load("visitor")
+	loader.load("visitor")
+	now = now.Add(time.Minute)
+	loader.load("visitor")
+
+	// Assert
+	clientMock.AssertNumberOfCalls(t, "GetRemoteVisitorDataWithFilter", 2)
+}
+
+func TestRemoteDataLoader_RetriesFailedFetchAfterTTL(t *testing.T) {
+	// Arrange
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("GetRemoteVisitorDataWithFilter", "visitor", true, mock.Anything, mock.Anything).
+		Return(nil, errors.New("timeout"))
+	now := time.Now()
+	loader := newRemoteDataLoader(clientMock, RemoteDataConfig{TTL: time.Minute})
+	loader.now = func() time.Time { return now }
+
+	// Act
+	loader.load("visitor")
+	loader.load("visitor")
+	now = now.Add(time.Minute)
+	loader.load("visitor")
+
+	// Assert
+	clientMock.AssertNumberOfCalls(t, "GetRemoteVisitorDataWithFilter", 2)
+}
+
+func TestRemoteDataLoader_SweepsExpiredFetches(t *testing.T) {
+	// Arrange
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("GetRemoteVisitorDataWithFilter", mock.Anything, true, mock.Anything, mock.Anything).
+		Return([]types.Data{}, nil)
+	now := time.Now()
+	loader := newRemoteDataLoader(clientMock, RemoteDataConfig{TTL: time.Minute})
+	loader.now = func() time.Time { return now }
+	loader.load("first")
+
+	// Act
+	now = now.Add(time.Minute)
+	loader.load("second")
+
+	// Assert
+	assert.Len(t, loader.fetches, 1)
+	assert.Contains(t, loader.fetches, "second")
+}
+
+func TestRemoteDataLoader_Background_DoesNotWaitForFetch(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("GetRemoteVisitorDataWithFilter", "visitor", true, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { <-release }).Return([]types.Data{}, nil)
+	loader := newRemoteDataLoader(clientMock, RemoteDataConfig{Background: true})
+
+	// Act
+	loader.load("visitor")
+	loader.load("visitor")
+	fetch := loader.fetches["visitor"]
+	close(release)
+	<-fetch.done
+
+	// Assert
+	clientMock.AssertNumberOfCalls(t, "GetRemoteVisitorDataWithFilter", 1)
+}
+
+func TestKameleoonProvider_RemoteVisitorData_FetchedBeforeEvaluation(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"fetched", nil},
+		{"fetch failure", errors.New("timeout")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var fetched bool
+			clientMock := new(MockKameleoonClient)
+			clientMock.On("OnUpdateConfiguration", mock.Anything)
+			clientMock.On("GetRemoteVisitorDataWithFilter", "visitor", true, mock.Anything, mock.Anything).
+				Run(func(mock.Arguments) { fetched = true }).Return([]types.Data{}, tt.err)
+			clientMock.On("AddData", "visitor", []types.Data(nil)).Return(nil)
+			clientMock.On("GetFeatureVariationKey", "visitor", "banner", []bool(nil)).
+				Run(func(mock.Arguments) { assert.True(t, fetched) }).Return("on", nil)
+			clientMock.On("GetFeatureVariationVariables", "banner", "on").
+				Return(map[string]interface{}{"enabled": true}, nil)
+			provider := NewKameleoonProviderWithClient("siteCode", clientMock, WithRemoteVisitorData(RemoteDataConfig{}))
+
+			// Act
+			result := provider.BooleanEvaluation(
+				context.Background(), "banner", false, openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+			// Assert
+			assert.True(t, result.Value)
+			assert.Empty(t, result.ResolutionDetail().ErrorCode)
+			clientMock.AssertNumberOfCalls(t, "GetRemoteVisitorDataWithFilter", 1)
+		})
+	}
+}
+
+func TestKameleoonProvider_RemoteVisitorData_NotFetchedInPeekMode(t *testing.T) {
+	// Arrange
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything)
+	clientMock.On("AddData", "visitor", []types.Data(nil)).Return(nil)
+	clientMock.On("GetFeatureVariationVariables", "banner", "off").Return(map[string]interface{}{}, nil)
+	clientMock.On("GetActiveFeatures", "visitor").Return(map[string]types.Variation{"banner": {Key: "on"}}, nil)
+	clientMock.On("GetFeatureVariationVariables", "banner", "on").
+		Return(map[string]interface{}{"enabled": true}, nil)
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock, WithRemoteVisitorData(RemoteDataConfig{}))
+
+	// Act
+	result := provider.BooleanEvaluation(
+		WithPeek(context.Background()), "banner", false, openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+	// Assert
+	assert.True(t, result.Value)
+	clientMock.AssertNotCalled(t, "GetRemoteVisitorDataWithFilter", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything)
+}
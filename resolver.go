@@ -2,11 +2,14 @@ package kameleoon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
 
 	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/errs"
+	"github.com/Kameleoon/client-go/v3/types"
 	"github.com/open-feature/go-sdk/openfeature"
 )
 
@@ -17,6 +20,38 @@ type resolver interface {
 	) (interface{}, *openfeature.ResolutionError, string)
 }
 
+// detailedResolver is implemented by resolvers which can report more than the value, error and variant.
+type detailedResolver interface {
+	resolveDetail(
+		ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext,
+	) resolution
+}
+
+// resolution holds the full result of a flag resolution.
+type resolution struct {
+	value    interface{}
+	err      *openfeature.ResolutionError
+	variant  string
+	reason   openfeature.Reason
+	metadata openfeature.FlagMetadata
+}
+
+// unpack returns the value, error and variant in the order used by the resolver interface.
+func (r resolution) unpack() (interface{}, *openfeature.ResolutionError, string) {
+	return r.value, r.err, r.variant
+}
+
+// resolveDetail resolves the flag by the given resolver, using the detailed result if the resolver provides it.
+func resolveDetail(
+	r resolver, ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext,
+) resolution {
+	if dr, ok := r.(detailedResolver); ok {
+		return dr.resolveDetail(ctx, flag, defaultValue, evalCtx)
+	}
+	value, err, variant := r.Resolve(ctx, flag, defaultValue, evalCtx)
+	return resolution{value: value, err: err, variant: variant}
+}
+
 // kameleoonResolver makes evalutions based on provided data, conforms to Resolver interface
 type kameleoonResolver struct {
 	client kameleoon.KameleoonClient
@@ -33,34 +68,80 @@ func newKameleoonResolver(client kameleoon.KameleoonClient) *kameleoonResolver {
 func (r *kameleoonResolver) Resolve(
 	context context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
 ) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(context, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *kameleoonResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
 	// Get visitor code from context.
 	visitorCode, ok := getTargetingKey(evalContext)
 	if !ok {
 		resError := openfeature.NewTargetingKeyMissingResolutionError(
 			"The TargetingKey is required in context and cannot be omitted.")
-		return defaultValue, &resError, ""
+		return resolution{value: defaultValue, err: &resError}
 	}
 
 	// Add targeting data from context to KameleoonClient by visitor code
 	data := ToKameleoon(evalContext)
 	err := r.client.AddData(visitorCode, data...)
 	if err != nil {
-		resError := openfeature.NewInvalidContextResolutionError(err.Error())
-		return defaultValue, &resError, ""
+		return sdkFailure(defaultValue, err, "", nil)
 	}
 
-	// Get a variant
-	variant, err := r.client.GetFeatureVariationKey(visitorCode, flag)
+	// Get a variant, without tracking the exposure if the evaluation is untracked
+	var variant string
+	if isUntracked(ctx) {
+		variant, err = untrackedVariationKey(r.client, visitorCode, flag)
+	} else {
+		variant, err = r.client.GetFeatureVariationKey(visitorCode, flag)
+	}
 	if err != nil {
-		resError := openfeature.NewFlagNotFoundResolutionError(err.Error())
-		return defaultValue, &resError, variant
+		return sdkFailure(defaultValue, err, variant, nil)
 	}
 
 	// Get the all variables for the variant
 	variables, err := r.client.GetFeatureVariationVariables(flag, variant)
 	if err != nil {
-		resError := openfeature.NewFlagNotFoundResolutionError(err.Error())
-		return defaultValue, &resError, variant
+		return sdkFailure(defaultValue, err, variant, nil)
+	}
+
+	return resolveVariable(ctx, evalContext, variables, variant, defaultValue)
+}
+
+// untrackedVariationKey returns the variation of the flag for the visitor without tracking the exposure.
+// The "off" variation is looked up first, so flags disabled in the environment and unknown flags fail with
+// the errors of GetFeatureVariationKey. The Kameleoon client has no untracked evaluation of a single flag,
+// so the variation is taken from GetActiveFeatures, which calculates the variations without saving them.
+func untrackedVariationKey(client kameleoon.KameleoonClient, visitorCode string, flag string) (string, error) {
+	var variationNotFound *errs.FeatureVariationNotFound
+	_, err := client.GetFeatureVariationVariables(flag, string(types.VariationOff))
+	if err != nil && !errors.As(err, &variationNotFound) {
+		return string(types.VariationOff), err
+	}
+	activeFeatures, err := client.GetActiveFeatures(visitorCode)
+	if err != nil {
+		return string(types.VariationOff), err
+	}
+	if variation, ok := activeFeatures[flag]; ok {
+		return variation.Key, nil
+	}
+	return string(types.VariationOff), nil
+}
+
+// resolveVariable picks the requested variable from the variation variables and checks its type.
+func resolveVariable(
+	ctx context.Context, evalContext openfeature.FlattenedContext, variables map[string]interface{}, variant string,
+	defaultValue interface{},
+) resolution {
+	// String evaluations requesting the variation key return it instead of a variable.
+	if res, ok := resolveVariationKey(evalContext, variant, defaultValue); ok {
+		return res
+	}
+
+	// Toggles without bool variable resolve boolean evaluations by their activation.
+	if res, ok := resolveActivation(ctx, evalContext, variables, variant, defaultValue); ok {
+		return res
 	}
 
 	// Get variableKey if it's provided in context or any first in variation.
@@ -68,29 +149,29 @@ func (r *kameleoonResolver) Resolve(
 	// variableKey is not provided.
 	variableKey := getVariableKey(evalContext, variables)
 
-	// Try to get value by variable key
+	// Try to get value by variable key, the "off" variation serves the default value if it lacks the variable
 	value, ok := variables[variableKey]
+	if (!ok || variableKey == "") && variant == string(types.VariationOff) {
+		return resolution{value: defaultValue, variant: variant, reason: openfeature.DefaultReason}
+	}
 	if !ok || variableKey == "" {
 		resError := openfeature.NewFlagNotFoundResolutionError(makeErrorDescription(variant, variableKey))
-		return defaultValue, &resError, variant
+		return resolution{value: defaultValue, err: &resError, variant: variant}
 	}
 
 	// Check if the variable value has a required type
 	if reflect.TypeOf(value) != reflect.TypeOf(defaultValue) {
 		resError := openfeature.NewTypeMismatchResolutionError(
 			"The type of value received is different from the requested value.")
-		return defaultValue, &resError, variant
+		return resolution{value: defaultValue, err: &resError, variant: variant}
 	}
 
-	return value, nil, variant
+	return resolution{value: value, variant: variant}
 }
 
 // getTargetingKey retrieves the targeting key from the provided evaluation context.
 func getTargetingKey(evalContext openfeature.FlattenedContext) (string, bool) {
-	if targetingKey, ok := evalContext["targetingKey"].(string); ok && targetingKey != "" {
-		return targetingKey, true
-	}
-	return "", false
+	return visitorCodeOf(evalContext["targetingKey"])
 }
 
 // getVariableKey retrieves the variable key from the provided context or variables map.
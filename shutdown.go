@@ -0,0 +1,117 @@
+package kameleoon
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/Kameleoon/client-go/v3/logging"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// defaultShutdownTimeout is how long Shutdown waits for pending visitor data to be flushed by default.
+const defaultShutdownTimeout = 5 * time.Second
+
+// WithShutdownTimeout sets how long Shutdown waits for the pending visitor data to be flushed,
+// 5 seconds by default. Data which isn't flushed in time is dropped and reported by ShutdownWithReport.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(o *providerOptions) {
+		o.shutdownTimeout = timeout
+	}
+}
+
+// ShutdownReport describes the flush of the pending visitor data made on shutdown.
+type ShutdownReport struct {
+	// Flushed lists the visitors whose pending data was handed to the Kameleoon client for sending. The client
+	// sends it in the background and doesn't report its delivery.
+	Flushed []string
+	// Dropped lists the visitors whose pending data wasn't flushed before the deadline.
+	Dropped []string
+	// TimedOut is set if the flush didn't finish before the deadline.
+	TimedOut bool
+}
+
+// ShutdownWithReport flushes the visitor data added through the provider, waiting at most the timeout set by
+// WithShutdownTimeout, then releases the client. The provider stays NOT_READY afterwards and evaluations
+// return PROVIDER_NOT_READY. Only the first call does the work, later calls return an empty report.
+func (p *kameleoonProvider) ShutdownWithReport() ShutdownReport {
+	var report ShutdownReport
+	p.shutdownOnce.Do(func() {
+		atomic.StoreInt32(&p.shutdown, 1)
+		if p.overrideFile != nil {
+			p.overrideFile.close()
+		}
+		if p.flusher != nil {
+			p.flusher.close()
+		}
+		if p.persister != nil {
+			p.persister.close()
+		}
+		report = p.flushPending()
+		if closer, ok := p.client.(interface{ close() }); ok {
+			closer.close()
+		}
+		if p.shared != nil {
+			p.shared.release()
+		}
+	})
+	return report
+}
+
+// flushPending flushes the pending visitor data within the shutdown timeout.
+func (p *kameleoonProvider) flushPending() ShutdownReport {
+	visitors := p.pending.drain()
+	if len(visitors) == 0 {
+		return ShutdownReport{}
+	}
+	done := make(chan struct{})
+	go func() {
+		p.client.FlushAll(true)
+		close(done)
+	}()
+	select {
+	case <-done:
+		return ShutdownReport{Flushed: visitors}
+	case <-time.After(p.shutdownTimeout):
+		logging.Warning("Kameleoon provider shut down before the data of %s visitors was flushed: %s",
+			len(visitors), visitors)
+		return ShutdownReport{Dropped: visitors, TimedOut: true}
+	}
+}
+
+// isShutDown reports whether the provider was shut down.
+func (p *kameleoonProvider) isShutDown() bool {
+	return atomic.LoadInt32(&p.shutdown) == 1
+}
+
+// shutdownResolver rejects evaluations once the provider is shut down.
+type shutdownResolver struct {
+	resolver resolver
+	shutDown func() bool
+}
+
+// newShutdownResolver creates a new instance of shutdownResolver.
+func newShutdownResolver(resolver resolver, shutDown func() bool) *shutdownResolver {
+	return &shutdownResolver{
+		resolver: resolver,
+		shutDown: shutDown,
+	}
+}
+
+// Resolve returns PROVIDER_NOT_READY if the provider was shut down, otherwise resolves the flag by
+// the wrapped resolver.
+func (r *shutdownResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *shutdownResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	if r.shutDown() {
+		resError := openfeature.NewProviderNotReadyResolutionError("The Kameleoon provider is shut down")
+		return resolution{value: defaultValue, err: &resError}
+	}
+	return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+}
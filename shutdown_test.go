@@ -0,0 +1,98 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newShutdownTestClient() *MockKameleoonClient {
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything)
+	clientMock.On("AddData", mock.Anything, mock.Anything).Return(nil)
+	clientMock.On("GetFeatureVariationKey", mock.Anything, "banner", []bool(nil)).Return("on", nil)
+	clientMock.On("GetFeatureVariationVariables", "banner", "on").
+		Return(map[string]interface{}{"enabled": true}, nil)
+	return clientMock
+}
+
+func TestKameleoonProvider_ShutdownWithReport_FlushesPendingVisitors(t *testing.T) {
+	// Arrange
+	clientMock := newShutdownTestClient()
+	clientMock.On("FlushAll", []bool{true})
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock)
+	provider.BooleanEvaluation(context.Background(), "banner", false,
+		openfeature.FlattenedContext{"targetingKey": "visitorB"})
+	provider.BooleanEvaluation(context.Background(), "banner", false,
+		openfeature.FlattenedContext{"targetingKey": "visitorA"})
+
+	// Act
+	report := provider.ShutdownWithReport()
+
+	// Assert
+	assert.Equal(t, ShutdownReport{Flushed: []string{"visitorA", "visitorB"}}, report)
+	clientMock.AssertCalled(t, "FlushAll", []bool{true})
+}
+
+func TestKameleoonProvider_ShutdownWithReport_ReportsDroppedVisitorsAfterDeadline(t *testing.T) {
+	// Arrange
+	unblock := make(chan time.Time)
+	defer close(unblock)
+	clientMock := newShutdownTestClient()
+	clientMock.On("FlushAll", []bool{true}).WaitUntil(unblock)
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock, WithShutdownTimeout(10*time.Millisecond))
+	provider.BooleanEvaluation(context.Background(), "banner", false,
+		openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+	// Act
+	report := provider.ShutdownWithReport()
+
+	// Assert
+	assert.True(t, report.TimedOut)
+	assert.Equal(t, []string{"visitor"}, report.Dropped)
+	assert.Empty(t, report.Flushed)
+}
+
+func TestKameleoonProvider_Shutdown_WithoutPendingData_DoesNotFlush(t *testing.T) {
+	// Arrange
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything)
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock)
+
+	// Act
+	report := provider.ShutdownWithReport()
+
+	// Assert
+	assert.Equal(t, ShutdownReport{}, report)
+	clientMock.AssertNotCalled(t, "FlushAll", mock.Anything)
+}
+
+func TestKameleoonProvider_AfterShutdown_IsTerminal(t *testing.T) {
+	// Arrange
+	provider := NewKameleoonProviderWithClient("siteCode", kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "banner",
+		DefaultVariation: "on",
+		Variations:       []kameleoontest.Variation{{Key: "on", Variables: map[string]interface{}{"enabled": true}}},
+	}))
+	evalContext := openfeature.FlattenedContext{"targetingKey": "visitor"}
+	provider.BooleanEvaluation(context.Background(), "banner", false, evalContext)
+	first := provider.ShutdownWithReport()
+
+	// Act
+	second := provider.ShutdownWithReport()
+	result := provider.BooleanEvaluation(context.Background(), "banner", false, evalContext)
+	_, snapshotErr := provider.Snapshot(context.Background(), openfeature.NewEvaluationContext("visitor", nil))
+
+	// Assert
+	assert.Equal(t, []string{"visitor"}, first.Flushed)
+	assert.Equal(t, ShutdownReport{}, second)
+	assert.Equal(t, openfeature.NotReadyState, provider.Status())
+	assert.False(t, result.Value)
+	assert.Equal(t, openfeature.ProviderNotReadyCode, result.ResolutionDetail().ErrorCode)
+	assert.Error(t, snapshotErr)
+}
@@ -0,0 +1,212 @@
+package kameleoon
+
+import (
+	"context"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// snapshotContextKey is the context.Context key of the evaluation snapshot.
+type snapshotContextKey struct{}
+
+// snapshotBuilderContextKey is the context.Context key of the snapshotBuilder filled by snapshotResolver.
+type snapshotBuilderContextKey struct{}
+
+// snapshotBuilder receives the snapshot calculated at the end of the resolver chain.
+type snapshotBuilder struct {
+	snapshot *evaluationSnapshot
+}
+
+// snapshotEntry is the frozen variation of a single feature flag.
+type snapshotEntry struct {
+	variant   string
+	variables map[string]interface{}
+	err       error
+}
+
+// evaluationSnapshot holds the frozen variations of all feature flags for one visitor.
+type evaluationSnapshot struct {
+	visitorCode string
+	entries     map[string]*snapshotEntry
+}
+
+// newEvaluationSnapshot calculates the variations of all feature flags for the visitor.
+// The variations of a tracked snapshot are calculated by GetFeatureVariationKey, which tracks the exposures
+// of the visitor to exactly the frozen variations, even if the configuration is refreshed later on.
+// An untracked snapshot uses GetActiveFeatures, which calculates the variations without tracking them.
+func newEvaluationSnapshot(
+	client kameleoon.KameleoonClient, visitorCode string, untracked bool,
+) (*evaluationSnapshot, error) {
+	snapshot := &evaluationSnapshot{
+		visitorCode: visitorCode,
+		entries:     make(map[string]*snapshotEntry),
+	}
+	if !untracked {
+		for _, flag := range client.GetFeatureList() {
+			snapshot.entries[flag] = newTrackedSnapshotEntry(client, visitorCode, flag)
+		}
+		return snapshot, nil
+	}
+	activeFeatures, err := client.GetActiveFeatures(visitorCode)
+	if err != nil {
+		return nil, err
+	}
+	for _, flag := range client.GetFeatureList() {
+		if variation, ok := activeFeatures[flag]; ok {
+			variables := make(map[string]interface{}, len(variation.Variables))
+			for key, variable := range variation.Variables {
+				variables[key] = variable.Value
+			}
+			snapshot.entries[flag] = &snapshotEntry{variant: variation.Key, variables: variables}
+		} else {
+			// Flags disabled in the environment aren't active, GetFeatureVariationVariables reports them
+			// with FeatureEnvironmentDisabled, so they keep the DISABLED reason.
+			variant := string(types.VariationOff)
+			variables, err := client.GetFeatureVariationVariables(flag, variant)
+			snapshot.entries[flag] = &snapshotEntry{variant: variant, variables: variables, err: err}
+		}
+	}
+	return snapshot, nil
+}
+
+// newTrackedSnapshotEntry calculates the variation of the flag for the visitor and tracks the exposure.
+// Flags disabled in the environment fail with FeatureEnvironmentDisabled, so they keep the DISABLED reason.
+func newTrackedSnapshotEntry(client kameleoon.KameleoonClient, visitorCode string, flag string) *snapshotEntry {
+	variant, err := client.GetFeatureVariationKey(visitorCode, flag)
+	if err != nil {
+		return &snapshotEntry{variant: variant, err: err}
+	}
+	variables, err := client.GetFeatureVariationVariables(flag, variant)
+	return &snapshotEntry{variant: variant, variables: variables, err: err}
+}
+
+// snapshotFromContext returns the evaluation snapshot stored in the context if there is one.
+func snapshotFromContext(ctx context.Context) *evaluationSnapshot {
+	if ctx == nil {
+		return nil
+	}
+	snapshot, _ := ctx.Value(snapshotContextKey{}).(*evaluationSnapshot)
+	return snapshot
+}
+
+// snapshotBuilderFromContext returns the snapshotBuilder stored in the context if there is one.
+func snapshotBuilderFromContext(ctx context.Context) *snapshotBuilder {
+	if ctx == nil {
+		return nil
+	}
+	builder, _ := ctx.Value(snapshotBuilderContextKey{}).(*snapshotBuilder)
+	return builder
+}
+
+// Snapshot freezes the variations of all feature flags for the visitor of the evaluation context and
+// returns a copy of ctx carrying them.
+//
+// Evaluations for the same visitor made with the returned context are served from the snapshot with the
+// CACHED reason, so one request sees a consistent view even if the configuration is refreshed meanwhile.
+// The exposures of the visitor to the frozen variations of all flags are tracked when the snapshot is made,
+// unless the evaluation is untracked. Flags which didn't exist when the snapshot was made are resolved as usual.
+//
+// The evaluation context goes through the same steps as an evaluation, e.g. the targeting key policy,
+// the legal consent and the remote data, so the snapshot is calculated for the visitor the evaluations use.
+func (p *kameleoonProvider) Snapshot(
+	ctx context.Context, evalCtx openfeature.EvaluationContext,
+) (context.Context, error) {
+	builder := &snapshotBuilder{}
+	builderCtx := context.WithValue(ctx, snapshotBuilderContextKey{}, builder)
+	res := resolveDetail(p.resolver, builderCtx, "", nil, flattenEvaluationContext(evalCtx))
+	if res.err != nil {
+		return ctx, *res.err
+	}
+	if builder.snapshot == nil {
+		return ctx, openfeature.NewGeneralResolutionError("The evaluation snapshot wasn't calculated")
+	}
+	return context.WithValue(ctx, snapshotContextKey{}, builder.snapshot), nil
+}
+
+// snapshotResolver serves evaluations from the snapshot stored in the context and delegates the others
+// to the wrapped resolver. It also calculates the snapshot requested by Snapshot.
+type snapshotResolver struct {
+	client   kameleoon.KameleoonClient
+	resolver resolver
+}
+
+// newSnapshotResolver creates a new instance of snapshotResolver.
+func newSnapshotResolver(client kameleoon.KameleoonClient, resolver resolver) *snapshotResolver {
+	return &snapshotResolver{
+		client:   client,
+		resolver: resolver,
+	}
+}
+
+// Resolve returns the snapshot result if the context carries one for the visitor and flag, otherwise
+// resolves the flag by the wrapped resolver.
+func (r *snapshotResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *snapshotResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	if builder := snapshotBuilderFromContext(ctx); builder != nil {
+		return r.build(ctx, builder, defaultValue, evalContext)
+	}
+	snapshot := snapshotFromContext(ctx)
+	visitorCode, ok := getTargetingKey(evalContext)
+	if snapshot == nil || !ok || snapshot.visitorCode != visitorCode {
+		return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+	}
+	entry, ok := snapshot.entries[flag]
+	if !ok {
+		return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+	}
+
+	// Data such as conversions must still reach the visitor even if the variation is frozen.
+	if err := r.client.AddData(visitorCode, ToKameleoon(evalContext)...); err != nil {
+		return sdkFailure(defaultValue, err, "", nil)
+	}
+	if entry.err != nil {
+		return sdkFailure(defaultValue, entry.err, entry.variant, nil)
+	}
+	res := resolveVariable(ctx, evalContext, entry.variables, entry.variant, defaultValue)
+	if res.err == nil {
+		res.reason = openfeature.CachedReason
+	}
+	return res
+}
+
+// build calculates the snapshot for the visitor of the evaluation context and stores it in the builder.
+func (r *snapshotResolver) build(
+	ctx context.Context, builder *snapshotBuilder, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	visitorCode, ok := getTargetingKey(evalContext)
+	if !ok {
+		resError := openfeature.NewTargetingKeyMissingResolutionError(
+			"The TargetingKey is required in context and cannot be omitted.")
+		return resolution{value: defaultValue, err: &resError, reason: openfeature.ErrorReason}
+	}
+	if err := r.client.AddData(visitorCode, ToKameleoon(evalContext)...); err != nil {
+		return sdkFailure(defaultValue, err, "", nil)
+	}
+	snapshot, err := newEvaluationSnapshot(r.client, visitorCode, isUntracked(ctx))
+	if err != nil {
+		return sdkFailure(defaultValue, err, "", nil)
+	}
+	builder.snapshot = snapshot
+	return resolution{value: defaultValue}
+}
+
+// flattenEvaluationContext converts the EvaluationContext to the FlattenedContext passed to providers.
+func flattenEvaluationContext(evalCtx openfeature.EvaluationContext) openfeature.FlattenedContext {
+	flatCtx := openfeature.FlattenedContext{}
+	for key, value := range evalCtx.Attributes() {
+		flatCtx[key] = value
+	}
+	if evalCtx.TargetingKey() != "" {
+		flatCtx["targetingKey"] = evalCtx.TargetingKey()
+	}
+	return flatCtx
+}
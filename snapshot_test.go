@@ -0,0 +1,165 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kameleoon/client-go/v3/errs"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupSnapshotClientMock(visitorCode string) *MockKameleoonClient {
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything)
+	clientMock.On("AddData", visitorCode, mock.Anything).Return(nil)
+	clientMock.On("GetFeatureList").Return([]string{"active", "inactive", "disabled"})
+	clientMock.On("GetActiveFeatures", visitorCode).Return(map[string]types.Variation{
+		"active": {Key: "treatment", Variables: map[string]types.Variable{
+			"color": {Key: "color", Type: "STRING", Value: "blue"},
+		}},
+	}, nil)
+	clientMock.On("GetFeatureVariationVariables", "inactive", "off").
+		Return(map[string]interface{}{"color": "grey"}, nil)
+	clientMock.On("GetFeatureVariationVariables", "disabled", "off").
+		Return(nil, errs.NewFeatureEnvironmentDisabled("disabled", ""))
+	clientMock.On("GetFeatureVariationKey", visitorCode, "active", []bool(nil)).Return("treatment", nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, "inactive", []bool(nil)).Return("off", nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, "disabled", []bool(nil)).
+		Return("off", errs.NewFeatureEnvironmentDisabled("disabled", ""))
+	clientMock.On("GetFeatureVariationVariables", "active", "treatment").
+		Return(map[string]interface{}{"color": "blue"}, nil)
+	return clientMock
+}
+
+func TestSnapshot_EvaluationsAreServedFromSnapshot(t *testing.T) {
+	// Arrange
+	visitorCode := "testVisitor"
+	clientMock := setupSnapshotClientMock(visitorCode)
+	provider := newProvider("siteCode", clientMock, newProviderOptions(nil))
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+
+	// Act
+	ctx, err := provider.Snapshot(context.Background(), openfeature.NewEvaluationContext(visitorCode, nil))
+	active := provider.StringEvaluation(ctx, "active", "", evalContext)
+	provider.StringEvaluation(ctx, "active", "", evalContext)
+	inactive := provider.StringEvaluation(ctx, "inactive", "", evalContext)
+	disabled := provider.StringEvaluation(ctx, "disabled", "", evalContext)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "blue", active.Value)
+	assert.Equal(t, "treatment", active.Variant)
+	assert.Equal(t, openfeature.CachedReason, active.Reason)
+	assert.Equal(t, "grey", inactive.Value)
+	assert.Equal(t, "off", inactive.Variant)
+	assert.NoError(t, disabled.Error())
+	assert.Equal(t, "", disabled.Value)
+	assert.Equal(t, "off", disabled.Variant)
+	assert.Equal(t, openfeature.DisabledReason, disabled.Reason)
+	clientMock.AssertNumberOfCalls(t, "GetFeatureVariationKey", 3)
+	clientMock.AssertNotCalled(t, "GetActiveFeatures", mock.Anything)
+}
+
+func TestSnapshot_Untracked_IsCalculatedWithoutTrackingExposures(t *testing.T) {
+	// Arrange
+	visitorCode := "testVisitor"
+	clientMock := setupSnapshotClientMock(visitorCode)
+	provider := newProvider("siteCode", clientMock, newProviderOptions(nil))
+
+	// Act
+	ctx, err := provider.Snapshot(WithPeek(context.Background()), openfeature.NewEvaluationContext(visitorCode, nil))
+	active := provider.StringEvaluation(ctx, "active", "", openfeature.FlattenedContext{"targetingKey": visitorCode})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "blue", active.Value)
+	assert.Equal(t, openfeature.CachedReason, active.Reason)
+	clientMock.AssertCalled(t, "GetActiveFeatures", visitorCode)
+	clientMock.AssertNotCalled(t, "GetFeatureVariationKey", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSnapshot_UsesVisitorCodeOfTargetingKeyPolicy(t *testing.T) {
+	// Arrange
+	visitorCode := "testVisitor"
+	clientMock := setupSnapshotClientMock(visitorCode)
+	provider := newProvider("siteCode", clientMock, newProviderOptions([]Option{WithTargetingKeyFallback("userId")}))
+
+	// Act
+	ctx, err := provider.Snapshot(context.Background(),
+		openfeature.NewTargetlessEvaluationContext(map[string]interface{}{"userId": visitorCode}))
+	active := provider.StringEvaluation(ctx, "active", "", openfeature.FlattenedContext{"userId": visitorCode})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "blue", active.Value)
+	assert.Equal(t, openfeature.CachedReason, active.Reason)
+}
+
+func TestSnapshot_ConfigurationChangeAfterSnapshotIsNotVisible(t *testing.T) {
+	// Arrange
+	visitorCode := "testVisitor"
+	clientMock := setupSnapshotClientMock(visitorCode)
+	provider := newProvider("siteCode", clientMock, newProviderOptions(nil))
+	ctx, _ := provider.Snapshot(context.Background(), openfeature.NewEvaluationContext(visitorCode, nil))
+
+	clientMock.ExpectedCalls = nil
+	clientMock.On("AddData", visitorCode, mock.Anything).Return(nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, "active", []bool(nil)).Return("control", nil)
+	clientMock.On("GetFeatureVariationVariables", "active", "control").
+		Return(map[string]interface{}{"color": "red"}, nil)
+	evalContext := openfeature.FlattenedContext{"targetingKey": visitorCode}
+
+	// Act
+	withSnapshot := provider.StringEvaluation(ctx, "active", "", evalContext)
+	withoutSnapshot := provider.StringEvaluation(context.Background(), "active", "", evalContext)
+
+	// Assert
+	assert.Equal(t, "blue", withSnapshot.Value)
+	assert.Equal(t, "red", withoutSnapshot.Value)
+	// The exposures were tracked with the frozen variations, the snapshot evaluation doesn't evaluate again.
+	clientMock.AssertNumberOfCalls(t, "GetFeatureVariationKey", 4)
+}
+
+func TestSnapshot_OtherVisitorAndUnknownFlagAreResolvedAsUsual(t *testing.T) {
+	// Arrange
+	visitorCode := "testVisitor"
+	otherVisitorCode := "otherVisitor"
+	clientMock := setupSnapshotClientMock(visitorCode)
+	clientMock.On("AddData", otherVisitorCode, mock.Anything).Return(nil)
+	clientMock.On("GetFeatureVariationKey", otherVisitorCode, "active", []bool(nil)).Return("control", nil)
+	clientMock.On("GetFeatureVariationKey", visitorCode, "newFlag", []bool(nil)).Return("on", nil)
+	clientMock.On("GetFeatureVariationVariables", "active", "control").
+		Return(map[string]interface{}{"color": "red"}, nil)
+	clientMock.On("GetFeatureVariationVariables", "newFlag", "on").
+		Return(map[string]interface{}{"color": "green"}, nil)
+	provider := newProvider("siteCode", clientMock, newProviderOptions(nil))
+	ctx, _ := provider.Snapshot(context.Background(), openfeature.NewEvaluationContext(visitorCode, nil))
+
+	// Act
+	otherVisitor := provider.StringEvaluation(ctx, "active", "",
+		openfeature.FlattenedContext{"targetingKey": otherVisitorCode})
+	newFlag := provider.StringEvaluation(ctx, "newFlag", "",
+		openfeature.FlattenedContext{"targetingKey": visitorCode})
+
+	// Assert
+	assert.Equal(t, "red", otherVisitor.Value)
+	assert.Empty(t, otherVisitor.Reason)
+	assert.Equal(t, "green", newFlag.Value)
+	assert.Empty(t, newFlag.Reason)
+}
+
+func TestSnapshot_WithoutTargetingKey_ReturnsError(t *testing.T) {
+	// Arrange
+	provider := newProvider("siteCode", setupSnapshotClientMock(""), newProviderOptions(nil))
+	parent := context.Background()
+
+	// Act
+	ctx, err := provider.Snapshot(parent, openfeature.NewEvaluationContext("", nil))
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, parent, ctx)
+}
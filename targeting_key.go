@@ -0,0 +1,176 @@
+package kameleoon
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/Kameleoon/client-go/v3/utils"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// VisitorCodeMetadataKey is the flag metadata key with the visitor code of an evaluation, when it isn't
+// the targeting key of the evaluation context: it's generated, read from a fallback attribute or normalized.
+const VisitorCodeMetadataKey = "visitorCode"
+
+// WithTargetingKeyFallback sets the ordered context attributes, e.g. "userId" or "sessionId", read as visitor code
+// when the evaluation context lacks the targeting key. String and integer values are accepted.
+func WithTargetingKeyFallback(attributes ...string) Option {
+	return func(o *providerOptions) {
+		o.targetingKeyFallback = append([]string(nil), attributes...)
+	}
+}
+
+// WithAnonymousVisitors generates a visitor code for evaluations without targeting key nor fallback attribute,
+// instead of failing with TARGETING_KEY_MISSING. The generated visitor code is reported in the flag metadata
+// under VisitorCodeMetadataKey.
+//
+// The visitor code is only stable within a snapshot or a transaction context: evaluations made with the same
+// one share the same anonymous visitor. Any other evaluation generates a new visitor code, so a new visitor
+// is exposed and tracked each time, and the variations may change from one evaluation to the next. Callers
+// must persist the reported visitor code and pass it as targeting key of the following evaluations.
+func WithAnonymousVisitors() Option {
+	return func(o *providerOptions) {
+		o.anonymousVisitors = true
+	}
+}
+
+// visitorCodeOf converts a targeting key value to a visitor code, accepting strings and integers.
+func visitorCodeOf(value interface{}) (string, bool) {
+	var visitorCode string
+	switch v := value.(type) {
+	case string:
+		visitorCode = v
+	case int:
+		visitorCode = strconv.Itoa(v)
+	case int32:
+		visitorCode = strconv.FormatInt(int64(v), 10)
+	case int64:
+		visitorCode = strconv.FormatInt(v, 10)
+	case uint:
+		visitorCode = strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		visitorCode = strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		visitorCode = strconv.FormatUint(v, 10)
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return "", false
+		}
+		visitorCode = strconv.FormatInt(int64(v), 10)
+	case json.Number:
+		if _, err := v.Int64(); err != nil {
+			return "", false
+		}
+		visitorCode = v.String()
+	}
+	return visitorCode, visitorCode != ""
+}
+
+// targetingKeyPolicy finds and normalizes the visitor code of evaluation contexts.
+type targetingKeyPolicy struct {
+	fallback      []string
+	anonymous     bool
+	normalization VisitorCodeNormalization
+}
+
+// newTargetingKeyPolicy creates a new instance of targetingKeyPolicy.
+func newTargetingKeyPolicy(
+	fallback []string, anonymous bool, normalization VisitorCodeNormalization,
+) *targetingKeyPolicy {
+	return &targetingKeyPolicy{fallback: fallback, anonymous: anonymous, normalization: normalization}
+}
+
+// apply returns the evaluation context with the visitor code read from lookup: the targeting key, then
+// the fallback attributes, then in anonymous mode the given anonymous visitor code or a generated one.
+// The visitor code is normalized, and returned if it isn't the targeting key of lookup.
+func (p *targetingKeyPolicy) apply(
+	evalContext openfeature.FlattenedContext, lookup openfeature.FlattenedContext, anonymousVisitorCode string,
+) (openfeature.FlattenedContext, string, error) {
+	targetingKey, ok := getTargetingKey(lookup)
+	for i := 0; !ok && i < len(p.fallback); i++ {
+		targetingKey, ok = visitorCodeOf(lookup[p.fallback[i]])
+	}
+	if !ok {
+		if !p.anonymous {
+			return evalContext, "", nil
+		}
+		if anonymousVisitorCode == "" {
+			anonymousVisitorCode = utils.GenerateVisitorCode()
+		}
+		targetingKey = anonymousVisitorCode
+	}
+	visitorCode, err := NormalizeVisitorCode(targetingKey, p.normalization)
+	if err != nil {
+		return evalContext, "", err
+	}
+	if current, _ := getTargetingKey(lookup); visitorCode == current {
+		return evalContext, "", nil
+	}
+	result := make(openfeature.FlattenedContext, len(evalContext)+1)
+	for key, value := range evalContext {
+		result[key] = value
+	}
+	result["targetingKey"] = visitorCode
+	return result, visitorCode, nil
+}
+
+// applyContext applies the policy with the attributes of the transaction context carried by ctx. The visitor
+// of the snapshot carried by ctx is reused as anonymous visitor, so the snapshot serves anonymous evaluations,
+// otherwise the anonymous visitor of the transaction context is.
+func (p *targetingKeyPolicy) applyContext(
+	ctx context.Context, evalContext openfeature.FlattenedContext,
+) (openfeature.FlattenedContext, string, error) {
+	lookup := evalContext
+	if tx := transactionFromContext(ctx); tx != nil {
+		lookup = tx.merge(evalContext)
+	}
+	var anonymousVisitorCode string
+	if snapshot := snapshotFromContext(ctx); snapshot != nil {
+		anonymousVisitorCode = snapshot.visitorCode
+	} else if tx := transactionFromContext(ctx); tx != nil && p.anonymous {
+		anonymousVisitorCode = tx.anonymousVisitorCode()
+	}
+	return p.apply(evalContext, lookup, anonymousVisitorCode)
+}
+
+// targetingKeyResolver resolves flags with the visitor code found and normalized by the targeting key policy.
+type targetingKeyResolver struct {
+	policy   *targetingKeyPolicy
+	resolver resolver
+}
+
+// newTargetingKeyResolver creates a new instance of targetingKeyResolver.
+func newTargetingKeyResolver(policy *targetingKeyPolicy, resolver resolver) *targetingKeyResolver {
+	return &targetingKeyResolver{
+		policy:   policy,
+		resolver: resolver,
+	}
+}
+
+// Resolve resolves the flag by the wrapped resolver with the visitor code found by the policy.
+func (r *targetingKeyResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *targetingKeyResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	evalContext, visitorCode, err := r.policy.applyContext(ctx, evalContext)
+	if err != nil {
+		return sdkFailure(defaultValue, err, "", nil)
+	}
+	res := resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+	if visitorCode == "" {
+		return res
+	}
+	metadata := openfeature.FlagMetadata{VisitorCodeMetadataKey: visitorCode}
+	for key, value := range res.metadata {
+		metadata[key] = value
+	}
+	res.metadata = metadata
+	return res
+}
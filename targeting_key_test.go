@@ -0,0 +1,179 @@
+package kameleoon
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisitorCodeOf_AcceptsStringsAndIntegers(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       interface{}
+		visitorCode string
+		ok          bool
+	}{
+		{"string", "visitor", "visitor", true},
+		{"empty string", "", "", false},
+		{"int", 42, "42", true},
+		{"int64", int64(-7), "-7", true},
+		{"uint64", uint64(9), "9", true},
+		{"integral float", 1234.0, "1234", true},
+		{"fractional float", 12.5, "", false},
+		{"json number", json.Number("123"), "123", true},
+		{"json float", json.Number("1.5"), "", false},
+		{"bool", true, "", false},
+		{"missing", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			visitorCode, ok := visitorCodeOf(tt.value)
+
+			// Assert
+			assert.Equal(t, tt.visitorCode, visitorCode)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestTargetingKeyPolicy_Apply_ReadsFallbackInOrder(t *testing.T) {
+	policy := newTargetingKeyPolicy([]string{"userId", "sessionId"}, false, VisitorCodeStrict)
+	tests := []struct {
+		name        string
+		evalContext openfeature.FlattenedContext
+		expected    interface{}
+	}{
+		{"targeting key", openfeature.FlattenedContext{"targetingKey": "visitor", "userId": "user"}, "visitor"},
+		{"first fallback", openfeature.FlattenedContext{"userId": 42, "sessionId": "session"}, "42"},
+		{"second fallback", openfeature.FlattenedContext{"userId": "", "sessionId": "session"}, "session"},
+		{"none", openfeature.FlattenedContext{"other": "value"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result, _, err := policy.apply(tt.evalContext, tt.evalContext, "")
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result["targetingKey"])
+		})
+	}
+}
+
+func TestTargetingKeyPolicy_Apply_WithoutFallbackNorAnonymous_KeepsContext(t *testing.T) {
+	// Arrange
+	policy := newTargetingKeyPolicy(nil, false, VisitorCodeStrict)
+
+	// Act
+	evalContext, visitorCode, err := policy.apply(openfeature.FlattenedContext{}, openfeature.FlattenedContext{}, "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, evalContext)
+	assert.Empty(t, visitorCode)
+}
+
+func TestKameleoonProvider_WithTargetingKeyFallback_EvaluatesWithFallback(t *testing.T) {
+	// Arrange
+	client := kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "banner",
+		DefaultVariation: "on",
+		Variations: []kameleoontest.Variation{
+			{Key: "on", Variables: map[string]interface{}{"enabled": true}},
+			{Key: "hidden", Variables: map[string]interface{}{"enabled": false}},
+		},
+	})
+	client.ForceVariation("42", "banner", "hidden")
+	provider := NewKameleoonProviderWithClient("siteCode", client, WithTargetingKeyFallback("userId"))
+	defer provider.Shutdown()
+
+	// Act
+	result := provider.BooleanEvaluation(context.Background(), "banner", true,
+		openfeature.FlattenedContext{"userId": 42})
+
+	// Assert
+	assert.NoError(t, result.Error())
+	assert.False(t, result.Value)
+	assert.Equal(t, "hidden", result.Variant)
+	assert.Equal(t, "42", result.FlagMetadata[VisitorCodeMetadataKey])
+}
+
+func TestKameleoonProvider_WithTargetingKeyFallback_ReadsTransactionContext(t *testing.T) {
+	// Arrange
+	client := newTransactionTestClient()
+	provider := NewKameleoonProviderWithClient("siteCode", client, WithTargetingKeyFallback("userId"))
+	defer provider.Shutdown()
+	ctx := WithTransaction(context.Background(),
+		openfeature.NewEvaluationContext("", map[string]interface{}{"userId": "user"}))
+
+	// Act
+	result := provider.BooleanEvaluation(ctx, "banner", false, openfeature.FlattenedContext{})
+
+	// Assert
+	assert.NoError(t, result.Error())
+	assert.True(t, result.Value)
+}
+
+func TestKameleoonProvider_WithoutTargetingKey_FailsUnlessAnonymous(t *testing.T) {
+	// Arrange
+	client := newTransactionTestClient()
+	strict := NewKameleoonProviderWithClient("siteCode", client)
+	defer strict.Shutdown()
+	anonymous := NewKameleoonProviderWithClient("siteCode", client, WithAnonymousVisitors())
+	defer anonymous.Shutdown()
+
+	// Act
+	strictResult := strict.BooleanEvaluation(context.Background(), "banner", false, openfeature.FlattenedContext{})
+	anonymousResult := anonymous.BooleanEvaluation(context.Background(), "banner", false,
+		openfeature.FlattenedContext{})
+
+	// Assert
+	assert.Equal(t, openfeature.TargetingKeyMissingCode, strictResult.ResolutionDetail().ErrorCode)
+	assert.NoError(t, anonymousResult.Error())
+	assert.True(t, anonymousResult.Value)
+	visitorCode, err := anonymousResult.FlagMetadata.GetString(VisitorCodeMetadataKey)
+	require.NoError(t, err)
+	assert.NotEmpty(t, visitorCode)
+}
+
+func TestKameleoonProvider_WithAnonymousVisitors_ReusesSnapshotVisitor(t *testing.T) {
+	// Arrange
+	client := newTransactionTestClient()
+	provider := NewKameleoonProviderWithClient("siteCode", client, WithAnonymousVisitors())
+	defer provider.Shutdown()
+	ctx, err := provider.Snapshot(context.Background(), openfeature.EvaluationContext{})
+	require.NoError(t, err)
+
+	// Act
+	first := provider.BooleanEvaluation(ctx, "banner", false, openfeature.FlattenedContext{})
+	second := provider.BooleanEvaluation(ctx, "banner", false, openfeature.FlattenedContext{})
+
+	// Assert
+	assert.Equal(t, openfeature.CachedReason, first.Reason)
+	assert.Equal(t, first.FlagMetadata[VisitorCodeMetadataKey], second.FlagMetadata[VisitorCodeMetadataKey])
+}
+
+func TestKameleoonProvider_WithAnonymousVisitors_ReusesTransactionVisitor(t *testing.T) {
+	// Arrange
+	client := newTransactionTestClient()
+	provider := NewKameleoonProviderWithClient("siteCode", client, WithAnonymousVisitors())
+	defer provider.Shutdown()
+	ctx := WithTransaction(context.Background(), openfeature.EvaluationContext{})
+
+	// Act
+	first := provider.BooleanEvaluation(ctx, "banner", false, openfeature.FlattenedContext{})
+	second := provider.BooleanEvaluation(ctx, "banner", false, openfeature.FlattenedContext{})
+	other := provider.BooleanEvaluation(context.Background(), "banner", false, openfeature.FlattenedContext{})
+
+	// Assert
+	require.NoError(t, first.Error())
+	assert.NotEmpty(t, first.FlagMetadata[VisitorCodeMetadataKey])
+	assert.Equal(t, first.FlagMetadata[VisitorCodeMetadataKey], second.FlagMetadata[VisitorCodeMetadataKey])
+	assert.NotEqual(t, first.FlagMetadata[VisitorCodeMetadataKey], other.FlagMetadata[VisitorCodeMetadataKey])
+}
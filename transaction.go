@@ -0,0 +1,176 @@
+package kameleoon
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/Kameleoon/client-go/v3/utils"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// transactionContextKey is the context key of the transaction context.
+type transactionContextKey struct{}
+
+// transactionContextUserValue is the fasthttp user value of the transaction context,
+// fasthttp.RequestCtx only resolves string keys.
+const transactionContextUserValue = "kameleoonTransactionContext"
+
+// transactionContext is the evaluation context of a transaction, e.g. an HTTP request. Its Kameleoon data
+// is converted once and added once per visitor, however many flags are evaluated during the transaction.
+type transactionContext struct {
+	evalCtx    openfeature.EvaluationContext
+	attributes openfeature.FlattenedContext
+	data       []types.Data
+
+	mu        sync.Mutex
+	added     map[transactionVisitor]struct{}
+	anonymous string
+}
+
+// anonymousVisitorCode returns the visitor code generated for the anonymous visitor of the transaction,
+// generating it on the first call, so all evaluations of the transaction share the same anonymous visitor.
+func (tx *transactionContext) anonymousVisitorCode() string {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.anonymous == "" {
+		tx.anonymous = utils.GenerateVisitorCode()
+	}
+	return tx.anonymous
+}
+
+// transactionVisitor identifies a visitor of a Kameleoon client the transaction data was added for.
+type transactionVisitor struct {
+	client      *trackingClient
+	visitorCode string
+}
+
+// newTransactionContext creates a new instance of transactionContext and converts its Kameleoon data.
+func newTransactionContext(evalCtx openfeature.EvaluationContext) *transactionContext {
+	flatCtx := flattenEvaluationContext(evalCtx)
+	attributes := make(openfeature.FlattenedContext, len(flatCtx))
+	for key, value := range flatCtx {
+		if _, ok := dc.conversionMethods[key]; !ok {
+			attributes[key] = value
+		}
+	}
+	return &transactionContext{
+		evalCtx:    evalCtx,
+		attributes: attributes,
+		data:       ToKameleoon(flatCtx),
+		added:      make(map[transactionVisitor]struct{}),
+	}
+}
+
+// WithTransaction returns a copy of ctx carrying the transaction evaluation context, e.g. the one of
+// an HTTP request. The provider merges its attributes into every evaluation made with the returned context,
+// attributes of the evaluation taking precedence. Its Kameleoon data, such as the device, browser, page view
+// or custom data, is converted once and added once per visitor instead of on every evaluation.
+func WithTransaction(ctx context.Context, evalCtx openfeature.EvaluationContext) context.Context {
+	return context.WithValue(ctx, transactionContextKey{}, newTransactionContext(evalCtx))
+}
+
+// TransactionEvaluationContext returns the transaction evaluation context carried by ctx, set by WithTransaction
+// or by the middleware. It's empty if there is none.
+func TransactionEvaluationContext(ctx context.Context) openfeature.EvaluationContext {
+	if tx := transactionFromContext(ctx); tx != nil {
+		return tx.evalCtx
+	}
+	return openfeature.EvaluationContext{}
+}
+
+// transactionFromContext returns the transaction context carried by ctx, nil if there is none.
+func transactionFromContext(ctx context.Context) *transactionContext {
+	if ctx == nil {
+		return nil
+	}
+	if tx, ok := ctx.Value(transactionContextKey{}).(*transactionContext); ok {
+		return tx
+	}
+	tx, _ := ctx.Value(transactionContextUserValue).(*transactionContext)
+	return tx
+}
+
+// merge returns the evaluation context with the transaction attributes it lacks.
+func (tx *transactionContext) merge(evalContext openfeature.FlattenedContext) openfeature.FlattenedContext {
+	merged := make(openfeature.FlattenedContext, len(tx.attributes)+len(evalContext))
+	for key, value := range tx.attributes {
+		merged[key] = value
+	}
+	for key, value := range evalContext {
+		merged[key] = value
+	}
+	return merged
+}
+
+// addData adds the transaction data for the visitor, unless it was already added to the client.
+func (tx *transactionContext) addData(client *trackingClient, visitorCode string) error {
+	if len(tx.data) == 0 {
+		return nil
+	}
+	visitor := transactionVisitor{client: client, visitorCode: visitorCode}
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if _, ok := tx.added[visitor]; ok {
+		return nil
+	}
+	if err := client.AddData(visitorCode, tx.data...); err != nil {
+		return err
+	}
+	tx.added[visitor] = struct{}{}
+	return nil
+}
+
+// applyTransactionContext merges the transaction context carried by ctx into the evaluation context and adds
+// the transaction data for the visitor, unless the visitor didn't give the legal consent or the evaluation
+// is untracked.
+func applyTransactionContext(
+	ctx context.Context, client *trackingClient, consentAttribute string, evalContext openfeature.FlattenedContext,
+) (openfeature.FlattenedContext, error) {
+	tx := transactionFromContext(ctx)
+	if tx == nil {
+		return evalContext, nil
+	}
+	merged := tx.merge(evalContext)
+	visitorCode, ok := getTargetingKey(merged)
+	if !ok {
+		return merged, nil
+	}
+	if consent, ok := legalConsent(merged, consentAttribute); (ok && !consent) || isUntracked(ctx) {
+		return merged, nil
+	}
+	return merged, tx.addData(client, visitorCode)
+}
+
+// transactionResolver applies the transaction context carried by the context of the evaluation.
+type transactionResolver struct {
+	client           *trackingClient
+	consentAttribute string
+	resolver         resolver
+}
+
+// newTransactionResolver creates a new instance of transactionResolver.
+func newTransactionResolver(client *trackingClient, consentAttribute string, resolver resolver) *transactionResolver {
+	return &transactionResolver{
+		client:           client,
+		consentAttribute: consentAttribute,
+		resolver:         resolver,
+	}
+}
+
+// Resolve resolves the flag by the wrapped resolver with the transaction context applied.
+func (r *transactionResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *transactionResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	evalContext, err := applyTransactionContext(ctx, r.client, r.consentAttribute, evalContext)
+	if err != nil {
+		return sdkFailure(defaultValue, err, "", nil)
+	}
+	return resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+}
@@ -0,0 +1,84 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTransactionTestClient() *kameleoontest.Client {
+	return kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "banner",
+		DefaultVariation: "on",
+		Variations: []kameleoontest.Variation{
+			{Key: "on", Variables: map[string]interface{}{"enabled": true}},
+		},
+	})
+}
+
+func TestKameleoonProvider_TransactionContext_AddsDataOnce(t *testing.T) {
+	// Arrange
+	client := newTransactionTestClient()
+	provider := NewKameleoonProviderWithClient("siteCode", client)
+	defer provider.Shutdown()
+	ctx := WithTransaction(context.Background(), openfeature.NewEvaluationContext("visitor",
+		map[string]interface{}{Data.Type.Device: "phone"}))
+
+	// Act
+	first := provider.BooleanEvaluation(ctx, "banner", false, openfeature.FlattenedContext{})
+	second := provider.BooleanEvaluation(ctx, "banner", false, openfeature.FlattenedContext{})
+
+	// Assert
+	assert.True(t, first.Value)
+	assert.True(t, second.Value)
+	data := client.VisitorData("visitor")
+	assert.Len(t, data, 1)
+	assert.Equal(t, types.DeviceTypePhone, data[0].(*types.Device).Type())
+}
+
+func TestKameleoonProvider_TransactionContext_EvaluationAttributesTakePrecedence(t *testing.T) {
+	// Arrange
+	client := newTransactionTestClient()
+	client.ForceVariation("known", "banner", kameleoontest.VariationOff)
+	provider := NewKameleoonProviderWithClient("siteCode", client)
+	defer provider.Shutdown()
+	ctx := WithTransaction(context.Background(), openfeature.NewEvaluationContext("visitor", nil))
+
+	// Act
+	transaction := provider.BooleanEvaluation(ctx, "banner", false, openfeature.FlattenedContext{})
+	evaluation := provider.BooleanEvaluation(ctx, "banner", false,
+		openfeature.FlattenedContext{"targetingKey": "known"})
+
+	// Assert
+	assert.Equal(t, "on", transaction.Variant)
+	assert.Equal(t, kameleoontest.VariationOff, evaluation.Variant)
+}
+
+func TestKameleoonProvider_TransactionContext_WithoutConsent_DoesNotAddData(t *testing.T) {
+	// Arrange
+	client := newTransactionTestClient()
+	provider := NewKameleoonProviderWithClient("siteCode", client)
+	defer provider.Shutdown()
+	ctx := WithTransaction(context.Background(), openfeature.NewEvaluationContext("visitor",
+		map[string]interface{}{Data.Type.Device: "phone", LegalConsentContextKey: false}))
+
+	// Act
+	result := provider.BooleanEvaluation(ctx, "banner", false, openfeature.FlattenedContext{})
+
+	// Assert
+	assert.True(t, result.Value)
+	assert.Empty(t, client.VisitorData("visitor"))
+}
+
+func TestTransactionContext_WithoutTransaction_ReturnsEmpty(t *testing.T) {
+	// Act
+	evalCtx := TransactionEvaluationContext(context.Background())
+
+	// Assert
+	assert.Empty(t, evalCtx.TargetingKey())
+	assert.Empty(t, evalCtx.Attributes())
+}
@@ -3,10 +3,14 @@ package kameleoon
 // Data is used to add different Kameleoon data types using
 // the FlattenedContext from the OpenFeature SDK.
 var Data = struct {
-	// Type is used to add Conversion and CustomData using FlattenedContext from the OpenFeature SDK.
+	// Type is used to add Conversion, CustomData, Device, Browser and PageView using FlattenedContext
+	// from the OpenFeature SDK.
 	Type struct {
 		Conversion string
 		CustomData string
+		Device     string
+		Browser    string
+		PageView   string
 	}
 	// CustomDataType is used to add CustomData using FlattenedContext from the OpenFeature SDK.
 	CustomDataType struct {
@@ -18,13 +22,30 @@ var Data = struct {
 		GoalId  string
 		Revenue string
 	}
+	// BrowserType is used to add Browser using FlattenedContext from the OpenFeature SDK.
+	BrowserType struct {
+		Type    string
+		Version string
+	}
+	// PageViewType is used to add PageView using FlattenedContext from the OpenFeature SDK.
+	PageViewType struct {
+		Url       string
+		Title     string
+		Referrers string
+	}
 }{
 	Type: struct {
 		Conversion string
 		CustomData string
+		Device     string
+		Browser    string
+		PageView   string
 	}{
 		Conversion: "conversion",
 		CustomData: "customData",
+		Device:     "device",
+		Browser:    "browser",
+		PageView:   "pageView",
 	},
 	CustomDataType: struct {
 		Index  string
@@ -40,4 +61,20 @@ var Data = struct {
 		GoalId:  "goalId",
 		Revenue: "revenue",
 	},
+	BrowserType: struct {
+		Type    string
+		Version string
+	}{
+		Type:    "type",
+		Version: "version",
+	},
+	PageViewType: struct {
+		Url       string
+		Title     string
+		Referrers string
+	}{
+		Url:       "url",
+		Title:     "title",
+		Referrers: "referrers",
+	},
 }
@@ -9,10 +9,20 @@ func TestCheckTypeValues_ProperValues(t *testing.T) {
 	// Assert
 	assert.Equal(t, "conversion", Data.Type.Conversion)
 	assert.Equal(t, "customData", Data.Type.CustomData)
+	assert.Equal(t, "device", Data.Type.Device)
+	assert.Equal(t, "browser", Data.Type.Browser)
+	assert.Equal(t, "pageView", Data.Type.PageView)
 
 	assert.Equal(t, "index", Data.CustomDataType.Index)
 	assert.Equal(t, "values", Data.CustomDataType.Values)
 
 	assert.Equal(t, "goalId", Data.ConversionType.GoalId)
 	assert.Equal(t, "revenue", Data.ConversionType.Revenue)
+
+	assert.Equal(t, "type", Data.BrowserType.Type)
+	assert.Equal(t, "version", Data.BrowserType.Version)
+
+	assert.Equal(t, "url", Data.PageViewType.Url)
+	assert.Equal(t, "title", Data.PageViewType.Title)
+	assert.Equal(t, "referrers", Data.PageViewType.Referrers)
 }
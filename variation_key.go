@@ -0,0 +1,52 @@
+package kameleoon
+
+import (
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// VariationKeyVariable is the reserved variableKey of string evaluations which return the variation key,
+// e.g. "control" or "treatment_b", instead of a variable value.
+const VariationKeyVariable = "$variation"
+
+// WithVariationKeyFlags makes string evaluations of the feature flags return the variation key, as if
+// VariationKeyVariable was requested, unless the evaluation context requests another variableKey.
+func WithVariationKeyFlags(flags ...string) Option {
+	return func(o *providerOptions) {
+		if o.variationKeyFlags == nil {
+			o.variationKeyFlags = make(map[string]struct{}, len(flags))
+		}
+		for _, flag := range flags {
+			o.variationKeyFlags[flag] = struct{}{}
+		}
+	}
+}
+
+// withVariationKey returns a copy of the evaluation context requesting the variation key, unless it requests
+// a variable key.
+func withVariationKey(evalContext openfeature.FlattenedContext) openfeature.FlattenedContext {
+	if variableKey, ok := evalContext["variableKey"].(string); ok && variableKey != "" {
+		return evalContext
+	}
+	result := make(openfeature.FlattenedContext, len(evalContext)+1)
+	for key, value := range evalContext {
+		result[key] = value
+	}
+	result["variableKey"] = VariationKeyVariable
+	return result
+}
+
+// resolveVariationKey resolves a string evaluation requesting VariationKeyVariable by the variation key.
+// It reports false for other evaluations.
+func resolveVariationKey(
+	evalContext openfeature.FlattenedContext, variant string, defaultValue interface{},
+) (resolution, bool) {
+	if variableKey, _ := evalContext["variableKey"].(string); variableKey != VariationKeyVariable {
+		return resolution{}, false
+	}
+	if _, ok := defaultValue.(string); !ok {
+		resError := openfeature.NewTypeMismatchResolutionError(
+			"The variation key can only be requested by string evaluations.")
+		return resolution{value: defaultValue, err: &resError, variant: variant}, true
+	}
+	return resolution{value: variant, variant: variant, reason: openfeature.TargetingMatchReason}, true
+}
@@ -0,0 +1,70 @@
+package kameleoon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kameleoon/openfeature-go/kameleoontest"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func newVariationKeyTestClient() *kameleoontest.Client {
+	client := kameleoontest.NewClient(kameleoontest.Feature{
+		Key:              "experiment",
+		DefaultVariation: "control",
+		Variations: []kameleoontest.Variation{
+			{Key: "control"},
+			{Key: "treatment_b", Variables: map[string]interface{}{"title": "New title"}},
+		},
+	})
+	client.ForceVariation("visitor", "experiment", "treatment_b")
+	return client
+}
+
+func TestKameleoonProvider_StringEvaluation_ReturnsVariationKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []Option
+		evalContext openfeature.FlattenedContext
+		value       string
+	}{
+		{"reserved variable key", nil,
+			openfeature.FlattenedContext{"targetingKey": "visitor", "variableKey": VariationKeyVariable}, "treatment_b"},
+		{"flag option", []Option{WithVariationKeyFlags("experiment")},
+			openfeature.FlattenedContext{"targetingKey": "visitor"}, "treatment_b"},
+		{"flag option without variables", []Option{WithVariationKeyFlags("experiment")},
+			openfeature.FlattenedContext{"targetingKey": "other"}, "control"},
+		{"requested variable key", []Option{WithVariationKeyFlags("experiment")},
+			openfeature.FlattenedContext{"targetingKey": "visitor", "variableKey": "title"}, "New title"},
+		{"variable value", nil, openfeature.FlattenedContext{"targetingKey": "visitor"}, "New title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			provider := NewKameleoonProviderWithClient("siteCode", newVariationKeyTestClient(), tt.options...)
+			defer provider.Shutdown()
+
+			// Act
+			result := provider.StringEvaluation(context.Background(), "experiment", "default", tt.evalContext)
+
+			// Assert
+			assert.NoError(t, result.Error())
+			assert.Equal(t, tt.value, result.Value)
+		})
+	}
+}
+
+func TestKameleoonProvider_VariationKey_NonStringEvaluation_ReturnsTypeMismatch(t *testing.T) {
+	// Arrange
+	provider := NewKameleoonProviderWithClient("siteCode", newVariationKeyTestClient())
+	defer provider.Shutdown()
+
+	// Act
+	result := provider.IntEvaluation(context.Background(), "experiment", 1,
+		openfeature.FlattenedContext{"targetingKey": "visitor", "variableKey": VariationKeyVariable})
+
+	// Assert
+	assert.Equal(t, openfeature.TypeMismatchCode, result.ResolutionDetail().ErrorCode)
+	assert.Equal(t, int64(1), result.Value)
+}
@@ -0,0 +1,91 @@
+package kameleoon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Kameleoon/client-go/v3/errs"
+	"github.com/Kameleoon/client-go/v3/utils"
+)
+
+// VisitorCodeNormalization is how targeting keys which aren't valid visitor codes are handled.
+// Valid visitor codes are non-empty UTF-8 strings of at most 255 bytes, without control characters.
+type VisitorCodeNormalization int
+
+const (
+	// VisitorCodeStrict fails evaluations with an invalid targeting key with INVALID_CONTEXT.
+	VisitorCodeStrict VisitorCodeNormalization = iota
+	// VisitorCodeTruncate removes invalid and control characters from invalid targeting keys and truncates them
+	// to 255 bytes. Distinct targeting keys may share the same visitor code.
+	VisitorCodeTruncate
+	// VisitorCodeHash replaces invalid targeting keys with their SHA-256 hash in hexadecimal.
+	VisitorCodeHash
+)
+
+// WithVisitorCodeNormalization sets how targeting keys which aren't valid visitor codes are handled,
+// VisitorCodeStrict by default. The visitor code of a normalized targeting key is reported in the flag metadata
+// under VisitorCodeMetadataKey.
+func WithVisitorCodeNormalization(normalization VisitorCodeNormalization) Option {
+	return func(o *providerOptions) {
+		o.visitorCodeNormalization = normalization
+	}
+}
+
+// NormalizeVisitorCode returns the visitor code the provider uses for the targeting key, the targeting key itself
+// if it's a valid visitor code. It's deterministic, so analytics can join visitor codes back to targeting keys.
+func NormalizeVisitorCode(targetingKey string, normalization VisitorCodeNormalization) (string, error) {
+	err := validateVisitorCode(targetingKey)
+	if err == nil || targetingKey == "" {
+		return targetingKey, err
+	}
+	switch normalization {
+	case VisitorCodeTruncate:
+		visitorCode := truncateVisitorCode(targetingKey)
+		if visitorCode == "" {
+			return "", err
+		}
+		return visitorCode, nil
+	case VisitorCodeHash:
+		hash := sha256.Sum256([]byte(targetingKey))
+		return hex.EncodeToString(hash[:]), nil
+	}
+	return "", err
+}
+
+// validateVisitorCode checks the visitor code is accepted by the Kameleoon SDK and can be stored in a cookie.
+func validateVisitorCode(visitorCode string) error {
+	if err := utils.ValidateVisitorCode(visitorCode); err != nil {
+		return err
+	}
+	if !utf8.ValidString(visitorCode) {
+		return errs.NewVisitorCodeInvalid("is not valid UTF-8")
+	}
+	for i, r := range visitorCode {
+		if unicode.IsControl(r) {
+			return errs.NewVisitorCodeInvalid(fmt.Sprintf("has a control character at byte %d", i))
+		}
+	}
+	return nil
+}
+
+// truncateVisitorCode removes invalid and control characters and truncates the result to the maximum length
+// of visitor codes, without splitting a character.
+func truncateVisitorCode(targetingKey string) string {
+	visitorCode := make([]byte, 0, utils.VisitorCodeMaxLength)
+	for i, r := range targetingKey {
+		if unicode.IsControl(r) {
+			continue
+		}
+		if _, size := utf8.DecodeRuneInString(targetingKey[i:]); r == utf8.RuneError && size == 1 {
+			continue
+		}
+		if len(visitorCode)+utf8.RuneLen(r) > utils.VisitorCodeMaxLength {
+			break
+		}
+		visitorCode = utf8.AppendRune(visitorCode, r)
+	}
+	return string(visitorCode)
+}
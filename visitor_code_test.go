@@ -0,0 +1,87 @@
+package kameleoon
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Kameleoon/client-go/v3/errs"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeVisitorCode(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	tests := []struct {
+		name          string
+		targetingKey  string
+		normalization VisitorCodeNormalization
+		visitorCode   string
+		err           string
+	}{
+		{"valid", "visitor", VisitorCodeHash, "visitor", ""},
+		{"empty", "", VisitorCodeTruncate, "", "is empty"},
+		{"strict too long", long, VisitorCodeStrict, "", "is longer than 255 chars"},
+		{"strict control character", "visi\ntor", VisitorCodeStrict, "", "has a control character at byte 4"},
+		{"strict invalid UTF-8", "visi\xfftor", VisitorCodeStrict, "", "is not valid UTF-8"},
+		{"truncate too long", long, VisitorCodeTruncate, long[:255], ""},
+		{"truncate multi-byte", strings.Repeat("é", 200), VisitorCodeTruncate, strings.Repeat("é", 127), ""},
+		{"truncate control character", "visi\ntor", VisitorCodeTruncate, "visitor", ""},
+		{"truncate invalid UTF-8", "visi\xfftor", VisitorCodeTruncate, "visitor", ""},
+		{"truncate only control characters", "\n\t", VisitorCodeTruncate, "", "has a control character at byte 0"},
+		{"hash", "visi\ntor", VisitorCodeHash, "8d08c98bc262e894f4c2c9e3abb7a351827b9e20e3146716eb359de789ed72e6", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			visitorCode, err := NormalizeVisitorCode(tt.targetingKey, tt.normalization)
+
+			// Assert
+			if tt.err != "" {
+				var invalid *errs.VisitorCodeInvalid
+				assert.ErrorAs(t, err, &invalid)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.visitorCode, visitorCode)
+		})
+	}
+}
+
+func TestKameleoonProvider_InvalidVisitorCode_FailsWithInvalidContext(t *testing.T) {
+	// Arrange
+	client := newTransactionTestClient()
+	provider := NewKameleoonProviderWithClient("siteCode", client)
+	defer provider.Shutdown()
+
+	// Act
+	result := provider.BooleanEvaluation(context.Background(), "banner", false,
+		openfeature.FlattenedContext{"targetingKey": strings.Repeat("a", 300)})
+
+	// Assert
+	assert.Equal(t, openfeature.InvalidContextCode, result.ResolutionDetail().ErrorCode)
+	assert.Contains(t, result.ResolutionDetail().ErrorMessage, "is longer than 255 chars")
+}
+
+func TestKameleoonProvider_WithVisitorCodeNormalization_ReportsVisitorCode(t *testing.T) {
+	// Arrange
+	client := newTransactionTestClient()
+	provider := NewKameleoonProviderWithClient("siteCode", client, WithVisitorCodeNormalization(VisitorCodeHash))
+	defer provider.Shutdown()
+	targetingKey := strings.Repeat("a", 300)
+	expected, err := NormalizeVisitorCode(targetingKey, VisitorCodeHash)
+	assert.NoError(t, err)
+
+	// Act
+	result := provider.BooleanEvaluation(context.Background(), "banner", false, openfeature.FlattenedContext{
+		"targetingKey":       targetingKey,
+		Data.Type.Conversion: map[string]interface{}{Data.ConversionType.GoalId: 1},
+	})
+
+	// Assert
+	assert.NoError(t, result.Error())
+	assert.True(t, result.Value)
+	assert.Equal(t, expected, result.FlagMetadata[VisitorCodeMetadataKey])
+	assert.Len(t, client.VisitorData(expected), 1)
+}
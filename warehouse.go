@@ -0,0 +1,189 @@
+package kameleoon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/logging"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// WarehouseKeyContextKey is the reserved context key with the key of the visitor in the data warehouse.
+// It takes precedence over WarehouseConfig.WarehouseKey.
+const WarehouseKeyContextKey = "kameleoonWarehouseKey"
+
+const (
+	// WarehouseErrorMetadataKey is the flag metadata key with the error message of a failed fetch of the warehouse
+	// audiences. The flag is evaluated without the audiences.
+	WarehouseErrorMetadataKey = "kameleoonWarehouseError"
+	// WarehouseErrorCodeMetadataKey is the flag metadata key with the OpenFeature error code of a failed fetch
+	// of the warehouse audiences.
+	WarehouseErrorCodeMetadataKey = "kameleoonWarehouseErrorCode"
+)
+
+// DefaultWarehouseTTL is how long the warehouse audiences of a visitor stay valid if WarehouseConfig.TTL isn't set.
+const DefaultWarehouseTTL = 30 * time.Minute
+
+// WarehouseConfig is used to configure the warehouse audience targeting.
+type WarehouseConfig struct {
+	// CustomDataIndex is the index of the custom data holding the warehouse audiences of the visitor.
+	CustomDataIndex int
+	// WarehouseKey is the key of the visitors in the data warehouse if the evaluation context lacks
+	// WarehouseKeyContextKey. The visitor code is used if it's empty.
+	WarehouseKey string
+	// Timeout of a fetch, the default timeout of the Kameleoon SDK if it isn't set.
+	Timeout time.Duration
+	// TTL is how long the warehouse audiences of a visitor stay valid before they're fetched again.
+	TTL time.Duration
+}
+
+// WithWarehouseAudience fetches the audiences of the visitor in the data warehouse before evaluations, which
+// the Kameleoon client adds to the visitor as the custom data at config.CustomDataIndex, so flags can target
+// warehouse audiences. The fetches are cached per visitor and warehouse key for the TTL: the fetched audiences
+// stay in the visitor data of the Kameleoon client, so a TTL longer than the visitor session of the client leaves
+// expired visitors without audiences until the TTL expires. A failed fetch doesn't fail the evaluation: the flag
+// is evaluated without the audiences and the error is reported in the flag metadata under WarehouseErrorMetadataKey
+// and WarehouseErrorCodeMetadataKey.
+// Untracked evaluations, in peek mode or for visitors without the legal consent, don't fetch the audiences.
+func WithWarehouseAudience(config WarehouseConfig) Option {
+	return func(o *providerOptions) {
+		o.warehouseConfig = &config
+	}
+}
+
+// warehouseAudienceKey identifies the warehouse audiences of a visitor.
+type warehouseAudienceKey struct {
+	visitorCode  string
+	warehouseKey string
+}
+
+// warehouseAudience is the result of a fetch of the warehouse audiences of a visitor.
+type warehouseAudience struct {
+	err       error
+	expiresAt time.Time
+}
+
+// warehouseAudiences fetches and caches the warehouse audiences of visitors.
+type warehouseAudiences struct {
+	client kameleoon.KameleoonClient
+	config WarehouseConfig
+
+	mu        sync.Mutex
+	entries   map[warehouseAudienceKey]warehouseAudience
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// newWarehouseAudiences creates a new instance of warehouseAudiences, applying defaults for unset values.
+func newWarehouseAudiences(client kameleoon.KameleoonClient, config WarehouseConfig) *warehouseAudiences {
+	if config.TTL <= 0 {
+		config.TTL = DefaultWarehouseTTL
+	}
+	return &warehouseAudiences{
+		client:  client,
+		config:  config,
+		entries: make(map[warehouseAudienceKey]warehouseAudience),
+		now:     time.Now,
+	}
+}
+
+// fetch fetches the warehouse audiences of the visitor unless they were fetched within the TTL, the Kameleoon
+// client adds them to the visitor. A failed fetch is cached too, so it's retried once the TTL expires.
+func (w *warehouseAudiences) fetch(visitorCode, warehouseKey string) error {
+	key := warehouseAudienceKey{visitorCode: visitorCode, warehouseKey: warehouseKey}
+	w.mu.Lock()
+	entry, ok := w.entries[key]
+	w.mu.Unlock()
+	if ok && w.now().Before(entry.expiresAt) {
+		return entry.err
+	}
+	_, err := w.client.GetVisitorWarehouseAudience(kameleoon.VisitorWarehouseAudienceParams{
+		VisitorCode:     visitorCode,
+		CustomDataIndex: w.config.CustomDataIndex,
+		WarehouseKey:    warehouseKey,
+		Timeout:         w.config.Timeout,
+	})
+	if err != nil {
+		logging.Warning("Failed to fetch the warehouse audiences of the visitor %s: %s", visitorCode, err)
+	}
+	entry = warehouseAudience{err: err}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.now()
+	entry.expiresAt = now.Add(w.config.TTL)
+	w.sweep(now)
+	w.entries[key] = entry
+	return entry.err
+}
+
+// sweep forgets the expired audiences at most once per TTL, the lock must be held.
+func (w *warehouseAudiences) sweep(now time.Time) {
+	if now.Sub(w.lastSweep) < w.config.TTL {
+		return
+	}
+	w.lastSweep = now
+	for key, entry := range w.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(w.entries, key)
+		}
+	}
+}
+
+// apply fetches the warehouse audiences of the visitor of the evaluation context. Nothing is fetched if the
+// context lacks the targeting key or if the evaluation is untracked. The error of a failed fetch is returned.
+func (w *warehouseAudiences) apply(ctx context.Context, evalContext openfeature.FlattenedContext) error {
+	if isUntracked(ctx) {
+		return nil
+	}
+	visitorCode, ok := getTargetingKey(evalContext)
+	if !ok {
+		return nil
+	}
+	warehouseKey, ok := evalContext[WarehouseKeyContextKey].(string)
+	if !ok || warehouseKey == "" {
+		warehouseKey = w.config.WarehouseKey
+	}
+	if err := w.fetch(visitorCode, warehouseKey); err != nil {
+		return TranslateError(err)
+	}
+	return nil
+}
+
+// warehouseResolver fetches the warehouse audiences of the visitor before the evaluation.
+type warehouseResolver struct {
+	audiences *warehouseAudiences
+	resolver  resolver
+}
+
+// newWarehouseResolver creates a new instance of warehouseResolver.
+func newWarehouseResolver(audiences *warehouseAudiences, resolver resolver) *warehouseResolver {
+	return &warehouseResolver{
+		audiences: audiences,
+		resolver:  resolver,
+	}
+}
+
+// Resolve resolves the flag by the wrapped resolver with the warehouse audiences of the visitor.
+func (r *warehouseResolver) Resolve(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) (interface{}, *openfeature.ResolutionError, string) {
+	return r.resolveDetail(ctx, flag, defaultValue, evalContext).unpack()
+}
+
+func (r *warehouseResolver) resolveDetail(
+	ctx context.Context, flag string, defaultValue interface{}, evalContext openfeature.FlattenedContext,
+) resolution {
+	err := r.audiences.apply(ctx, evalContext)
+	res := resolveDetail(r.resolver, ctx, flag, defaultValue, evalContext)
+	if err == nil {
+		return res
+	}
+	metadata := errorMetadata(TranslateError(err), WarehouseErrorMetadataKey, WarehouseErrorCodeMetadataKey)
+	for key, value := range res.metadata {
+		metadata[key] = value
+	}
+	res.metadata = metadata
+	return res
+}
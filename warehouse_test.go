@@ -0,0 +1,170 @@
+package kameleoon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kameleoon "github.com/Kameleoon/client-go/v3"
+	"github.com/Kameleoon/client-go/v3/types"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarehouseAudiences_Apply_FetchesAudiences(t *testing.T) {
+	tests := []struct {
+		name         string
+		untracked    bool
+		evalContext  openfeature.FlattenedContext
+		warehouseKey string
+	}{
+		{
+			name:         "config warehouse key",
+			evalContext:  openfeature.FlattenedContext{"targetingKey": "visitor"},
+			warehouseKey: "configKey",
+		},
+		{
+			name:         "context warehouse key",
+			evalContext:  openfeature.FlattenedContext{"targetingKey": "visitor", WarehouseKeyContextKey: "contextKey"},
+			warehouseKey: "contextKey",
+		},
+		{
+			name:        "untracked",
+			untracked:   true,
+			evalContext: openfeature.FlattenedContext{"targetingKey": "visitor"},
+		},
+		{
+			name:        "missing targeting key",
+			evalContext: openfeature.FlattenedContext{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			clientMock := new(MockKameleoonClient)
+			clientMock.On("GetVisitorWarehouseAudience", kameleoon.VisitorWarehouseAudienceParams{
+				VisitorCode: "visitor", CustomDataIndex: 5, WarehouseKey: tt.warehouseKey, Timeout: time.Second,
+			}).Return(types.NewCustomData(5, "vip"), nil)
+			audiences := newWarehouseAudiences(clientMock, WarehouseConfig{
+				CustomDataIndex: 5, WarehouseKey: "configKey", Timeout: time.Second,
+			})
+			ctx := context.Background()
+			if tt.untracked {
+				ctx, _ = withoutTracking(ctx, nil)
+			}
+
+			// Act
+			err := audiences.apply(ctx, tt.evalContext)
+
+			// Assert
+			require.NoError(t, err)
+			assert.NotContains(t, tt.evalContext, Data.Type.CustomData)
+			if tt.warehouseKey == "" {
+				clientMock.AssertNotCalled(t, "GetVisitorWarehouseAudience", mock.Anything)
+			} else {
+				clientMock.AssertNumberOfCalls(t, "GetVisitorWarehouseAudience", 1)
+			}
+		})
+	}
+}
+
+func TestWarehouseAudiences_Fetch_CachesResultsAndFailuresForTTL(t *testing.T) {
+	tests := []struct {
+		name       string
+		customData *types.CustomData
+		err        error
+	}{
+		{"audiences", types.NewCustomData(5, "vip"), nil},
+		{"failure", nil, errors.New("timeout")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			clientMock := new(MockKameleoonClient)
+			clientMock.On("GetVisitorWarehouseAudience", mock.Anything).Return(tt.customData, tt.err)
+			now := time.Now()
+			audiences := newWarehouseAudiences(clientMock, WarehouseConfig{CustomDataIndex: 5, TTL: time.Minute})
+			audiences.now = func() time.Time { return now }
+
+			// Act
+			audiences.fetch("visitor", "")
+			err := audiences.fetch("visitor", "")
+			now = now.Add(time.Minute)
+			audiences.fetch("visitor", "")
+
+			// Assert
+			assert.Equal(t, tt.err, err)
+			clientMock.AssertNumberOfCalls(t, "GetVisitorWarehouseAudience", 2)
+		})
+	}
+}
+
+func TestWarehouseAudiences_Fetch_CachesPerWarehouseKey(t *testing.T) {
+	// Arrange
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("GetVisitorWarehouseAudience", mock.Anything).Return(types.NewCustomData(5, "vip"), nil)
+	audiences := newWarehouseAudiences(clientMock, WarehouseConfig{CustomDataIndex: 5})
+
+	// Act
+	audiences.fetch("visitor", "first")
+	audiences.fetch("visitor", "second")
+
+	// Assert
+	clientMock.AssertNumberOfCalls(t, "GetVisitorWarehouseAudience", 2)
+}
+
+func TestKameleoonProvider_WarehouseAudience_FetchesAudiencesOncePerTTL(t *testing.T) {
+	// Arrange
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything)
+	clientMock.On("GetVisitorWarehouseAudience", mock.Anything).Return(types.NewCustomData(5, "vip"), nil)
+	clientMock.On("AddData", "visitor", []types.Data(nil)).Return(nil)
+	clientMock.On("GetFeatureVariationKey", "visitor", "banner", []bool(nil)).Return("on", nil)
+	clientMock.On("GetFeatureVariationVariables", "banner", "on").
+		Return(map[string]interface{}{"enabled": true}, nil)
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock,
+		WithWarehouseAudience(WarehouseConfig{CustomDataIndex: 5}))
+
+	// Act
+	provider.BooleanEvaluation(
+		context.Background(), "banner", false, openfeature.FlattenedContext{"targetingKey": "visitor"})
+	result := provider.BooleanEvaluation(
+		context.Background(), "banner", false, openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+	// Assert
+	assert.True(t, result.Value)
+	assert.NotContains(t, result.FlagMetadata, WarehouseErrorMetadataKey)
+	clientMock.AssertNumberOfCalls(t, "GetVisitorWarehouseAudience", 1)
+	clientMock.AssertNotCalled(t, "AddData", "visitor", []types.Data{types.NewCustomData(5, "vip")})
+}
+
+func TestKameleoonProvider_WarehouseAudience_ReportsFailureInMetadata(t *testing.T) {
+	// Arrange
+	fetchErr := errors.New("timeout")
+	clientMock := new(MockKameleoonClient)
+	clientMock.On("OnUpdateConfiguration", mock.Anything)
+	clientMock.On("GetVisitorWarehouseAudience", mock.Anything).Return(nil, fetchErr)
+	clientMock.On("AddData", "visitor", []types.Data(nil)).Return(nil)
+	clientMock.On("GetFeatureVariationKey", "visitor", "banner", []bool(nil)).Return("on", nil)
+	clientMock.On("GetFeatureVariationVariables", "banner", "on").
+		Return(map[string]interface{}{"enabled": true}, nil)
+	provider := NewKameleoonProviderWithClient("siteCode", clientMock,
+		WithWarehouseAudience(WarehouseConfig{CustomDataIndex: 5}))
+
+	// Act
+	result := provider.BooleanEvaluation(
+		context.Background(), "banner", false, openfeature.FlattenedContext{"targetingKey": "visitor"})
+
+	// Assert
+	assert.True(t, result.Value)
+	assert.Empty(t, result.ResolutionDetail().ErrorCode)
+	message, err := result.FlagMetadata.GetString(WarehouseErrorMetadataKey)
+	require.NoError(t, err)
+	assert.Equal(t, fetchErr.Error(), message)
+	code, err := result.FlagMetadata.GetString(WarehouseErrorCodeMetadataKey)
+	require.NoError(t, err)
+	assert.Equal(t, string(openfeature.GeneralCode), code)
+}